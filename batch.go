@@ -0,0 +1,48 @@
+package meow
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// batchSize is the number of inputs claimed at once by a ChecksumBatch worker.
+const batchSize = 64
+
+// ChecksumBatch returns the Meow checksums of inputs, in order. The work is
+// spread across a fixed pool of GOMAXPROCS goroutines.
+func ChecksumBatch(seed uint64, inputs [][]byte) [][Size]byte {
+	sums := make([][Size]byte, len(inputs))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(inputs) {
+		workers = len(inputs)
+	}
+
+	// Workers claim batchSize inputs at a time, to limit contention on the
+	// shared counter for small inputs.
+	var next int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				end := int(atomic.AddInt64(&next, batchSize))
+				start := end - batchSize
+				if start >= len(inputs) {
+					return
+				}
+				if end > len(inputs) {
+					end = len(inputs)
+				}
+				for i := start; i < end; i++ {
+					sums[i] = checksumOneShot(seed, inputs[i])
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return sums
+}
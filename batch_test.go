@@ -0,0 +1,26 @@
+package meow
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestChecksumBatch(t *testing.T) {
+	for _, n := range []int{0, 1, 1000} {
+		inputs := make([][]byte, n)
+		for i := range inputs {
+			inputs[i] = make([]byte, rand.Intn(1<<10))
+			rand.Read(inputs[i])
+		}
+
+		sums := ChecksumBatch(7, inputs)
+		if len(sums) != n {
+			t.Fatalf("got %d checksums expect=%d", len(sums), n)
+		}
+		for i, input := range inputs {
+			if expect := Checksum(7, input); sums[i] != expect {
+				t.Fatalf("input %d: got=%x expect=%x", i, sums[i], expect)
+			}
+		}
+	}
+}
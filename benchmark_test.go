@@ -2,6 +2,7 @@ package meow_test
 
 import (
 	"fmt"
+	"hash/maphash"
 	"testing"
 
 	"github.com/pckhoi/meow"
@@ -59,3 +60,147 @@ func BenchmarkSumTo(b *testing.B) {
 		return sum[0]
 	})
 }
+
+func BenchmarkSmallWrites(b *testing.B) {
+	h := meow.New(0)
+	field := buffer[:4]
+	b.ReportAllocs()
+	b.SetBytes(1000 * int64(len(field)))
+	for i := 0; i < b.N; i++ {
+		h.Reset()
+		for j := 0; j < 1000; j++ {
+			h.Write(field)
+		}
+		sink += byte(h.Sum64())
+	}
+}
+
+func BenchmarkAlignedWrites(b *testing.B) {
+	for _, size := range []int{meow.BlockSize, 16 * meow.BlockSize} {
+		for _, extra := range []int{0, 1} {
+			name := fmt.Sprintf("size=%d", size+extra)
+			data := buffer[:size+extra]
+			b.Run(name, func(b *testing.B) {
+				h := meow.New(0)
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data)))
+				for i := 0; i < b.N; i++ {
+					h.Write(data)
+				}
+				sink += byte(h.Sum64())
+			})
+		}
+	}
+}
+
+// BenchmarkSum64 measures a streaming 64-bit digest of a 128-byte input, with
+// the standard library's hash/maphash as a baseline.
+func BenchmarkSum64(b *testing.B) {
+	data := buffer[:128]
+	b.Run("meow", func(b *testing.B) {
+		h := meow.New64(0)
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			h.Reset()
+			h.Write(data)
+			sink += byte(h.Sum64())
+		}
+	})
+	b.Run("maphash", func(b *testing.B) {
+		var h maphash.Hash
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			h.Reset()
+			h.Write(data)
+			sink += byte(h.Sum64())
+		}
+	})
+}
+
+// BenchmarkFinish isolates the cost of finishing a 64-byte message with each
+// digest size and each Sum variant.
+func BenchmarkFinish(b *testing.B) {
+	data := buffer[:64]
+	digests := []struct {
+		Name string
+		New  func(uint64) *meow.Digest
+	}{
+		{"New", meow.New},
+		{"New64", meow.New64},
+		{"New32", meow.New32},
+	}
+	for _, d := range digests {
+		h := d.New(0)
+		h.Write(data)
+		buf := make([]byte, 0, meow.Size)
+		b.Run(d.Name+"/Sum", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				buf = h.Sum(buf[:0])
+				sink += buf[0]
+			}
+		})
+		b.Run(d.Name+"/Sum64", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink += byte(h.Sum64())
+			}
+		})
+		b.Run(d.Name+"/Sum32", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink += byte(h.Sum32())
+			}
+		})
+	}
+}
+
+func BenchmarkChecksumBatch(b *testing.B) {
+	inputs := make([][]byte, 10000)
+	for i := range inputs {
+		inputs[i] = buffer[i : i+64]
+	}
+	b.SetBytes(int64(len(inputs)) * 64)
+	for i := 0; i < b.N; i++ {
+		sums := meow.ChecksumBatch(0, inputs)
+		sink += sums[0][0]
+	}
+}
+
+func BenchmarkFixedSizeKeys(b *testing.B) {
+	b.Run("Checksum64/8", func(b *testing.B) {
+		var key [8]byte
+		for i := 0; i < b.N; i++ {
+			sink += byte(meow.Checksum64(0, key[:]))
+		}
+	})
+	b.Run("ChecksumUint64", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sink += byte(meow.ChecksumUint64(0, uint64(i)))
+		}
+	})
+	b.Run("Checksum64/16", func(b *testing.B) {
+		var key [16]byte
+		for i := 0; i < b.N; i++ {
+			sink += byte(meow.Checksum64(0, key[:]))
+		}
+	})
+	b.Run("ChecksumBytes16", func(b *testing.B) {
+		var key [16]byte
+		for i := 0; i < b.N; i++ {
+			sink += byte(meow.ChecksumBytes16(0, key))
+		}
+	})
+}
+
+func BenchmarkSmallChecksum(b *testing.B) {
+	for _, size := range []int{8, 16, 64, 255} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			data := buffer[:size]
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				h := meow.Checksum(0, data)
+				sink += h[0]
+			}
+		})
+	}
+}
@@ -4,15 +4,15 @@
 
 #include "textflag.h"
 
-TEXT ·checksum128(SB),0,$32-56
+TEXT ·checksum128(SB),0,$32-48
 #define SEED R8
 	MOVQ     seed+0(FP), SEED
-#define DST_PTR DI
-	MOVQ     dst_ptr+8(FP), DST_PTR
 #define SRC_PTR SI
-	MOVQ     src_ptr+32(FP), SRC_PTR
+	MOVQ     src_base+8(FP), SRC_PTR
 #define SRC_LEN AX
-	MOVQ     src_len+40(FP), SRC_LEN
+	MOVQ     src_len+16(FP), SRC_LEN
+#define DST_PTR DI
+	LEAQ     ret+32(FP), DST_PTR
 
 	// Backup total input length.
 #define TOTAL_LEN R9
@@ -213,9 +213,9 @@ combine:
 	MOVOU    X7, 0(DST_PTR)
 	RET
 #undef SEED
-#undef DST_PTR
 #undef SRC_PTR
 #undef SRC_LEN
+#undef DST_PTR
 #undef TOTAL_LEN
 #undef MIX0
 #undef MIX1
@@ -225,9 +225,9 @@ combine:
 
 TEXT ·blocks128(SB),0,$0-48
 #define S_PTR DI
-	MOVQ     s_ptr+0(FP), S_PTR
+	MOVQ     s_base+0(FP), S_PTR
 #define SRC_PTR SI
-	MOVQ     src_ptr+24(FP), SRC_PTR
+	MOVQ     src_base+24(FP), SRC_PTR
 #define SRC_LEN AX
 	MOVQ     src_len+32(FP), SRC_LEN
 	MOVOU    0(S_PTR), X0
@@ -294,29 +294,29 @@ done:
 #undef SRC_PTR
 #undef SRC_LEN
 
-TEXT ·checksum256(SB),0,$32-56
+TEXT ·checksum256(SB),0,$32-48
 #define SEED R8
 	MOVQ     seed+0(FP), SEED
-#define DST_PTR DI
-	MOVQ     dst_ptr+8(FP), DST_PTR
 #define SRC_PTR SI
-	MOVQ     src_ptr+32(FP), SRC_PTR
+	MOVQ     src_base+8(FP), SRC_PTR
 #define SRC_LEN AX
-	MOVQ     src_len+40(FP), SRC_LEN
+	MOVQ     src_len+16(FP), SRC_LEN
+#define DST_PTR DI
+	LEAQ     ret+32(FP), DST_PTR
 
 	// Backup total input length.
 #define TOTAL_LEN R9
 	MOVQ     SRC_LEN, TOTAL_LEN
 
 	// Load zero "IV".
-	VPXORQ   Y16, Y16, Y16
-	VPXORQ   Y17, Y17, Y17
-	VPXORQ   Y18, Y18, Y18
-	VPXORQ   Y19, Y19, Y19
-	VPXORQ   Y20, Y20, Y20
-	VPXORQ   Y21, Y21, Y21
-	VPXORQ   Y22, Y22, Y22
-	VPXORQ   Y23, Y23, Y23
+	VPXOR    Y8, Y8, Y8
+	VPXOR    Y9, Y9, Y9
+	VPXOR    Y10, Y10, Y10
+	VPXOR    Y11, Y11, Y11
+	VPXOR    Y12, Y12, Y12
+	VPXOR    Y13, Y13, Y13
+	VPXOR    Y14, Y14, Y14
+	VPXOR    Y15, Y15, Y15
 
 	// Handle full 256-byte blocks.
 
@@ -325,14 +325,14 @@ loop:
 	JB       sub256
 
 	// Hash block.
-	VAESDEC  0(SRC_PTR), Y16, Y16
-	VAESDEC  32(SRC_PTR), Y17, Y17
-	VAESDEC  64(SRC_PTR), Y18, Y18
-	VAESDEC  96(SRC_PTR), Y19, Y19
-	VAESDEC  128(SRC_PTR), Y20, Y20
-	VAESDEC  160(SRC_PTR), Y21, Y21
-	VAESDEC  192(SRC_PTR), Y22, Y22
-	VAESDEC  224(SRC_PTR), Y23, Y23
+	VAESDEC  0(SRC_PTR), Y8, Y8
+	VAESDEC  32(SRC_PTR), Y9, Y9
+	VAESDEC  64(SRC_PTR), Y10, Y10
+	VAESDEC  96(SRC_PTR), Y11, Y11
+	VAESDEC  128(SRC_PTR), Y12, Y12
+	VAESDEC  160(SRC_PTR), Y13, Y13
+	VAESDEC  192(SRC_PTR), Y14, Y14
+	VAESDEC  224(SRC_PTR), Y15, Y15
 
 	// Update source pointer.
 	ADDQ     $256, SRC_PTR
@@ -342,22 +342,23 @@ loop:
 	// Handle final sub 256-byte block.
 
 sub256:
-	VEXTRACTI32X4 $0, Y16, X0
-	VEXTRACTI32X4 $1, Y16, X1
-	VEXTRACTI32X4 $0, Y17, X2
-	VEXTRACTI32X4 $1, Y17, X3
-	VEXTRACTI32X4 $0, Y18, X4
-	VEXTRACTI32X4 $1, Y18, X5
-	VEXTRACTI32X4 $0, Y19, X6
-	VEXTRACTI32X4 $1, Y19, X7
-	VEXTRACTI32X4 $0, Y20, X8
-	VEXTRACTI32X4 $1, Y20, X9
-	VEXTRACTI32X4 $0, Y21, X10
-	VEXTRACTI32X4 $1, Y21, X11
-	VEXTRACTI32X4 $0, Y22, X12
-	VEXTRACTI32X4 $1, Y22, X13
-	VEXTRACTI32X4 $0, Y23, X14
-	VEXTRACTI32X4 $1, Y23, X15
+	VEXTRACTI128 $0, Y8, X0
+	VEXTRACTI128 $1, Y8, X1
+	VEXTRACTI128 $0, Y9, X2
+	VEXTRACTI128 $1, Y9, X3
+	VEXTRACTI128 $0, Y10, X4
+	VEXTRACTI128 $1, Y10, X5
+	VEXTRACTI128 $0, Y11, X6
+	VEXTRACTI128 $1, Y11, X7
+	VEXTRACTI128 $0, Y12, X8
+	VEXTRACTI128 $1, Y12, X9
+	VEXTRACTI128 $0, Y13, X10
+	VEXTRACTI128 $1, Y13, X11
+	VEXTRACTI128 $0, Y14, X12
+	VEXTRACTI128 $1, Y14, X13
+	VEXTRACTI128 $0, Y15, X14
+	VEXTRACTI128 $1, Y15, X15
+	VZEROUPPER
 
 	// Allocate general purpose registers.
 #define MIX0 R11
@@ -503,9 +504,9 @@ combine:
 	MOVOU    X7, 0(DST_PTR)
 	RET
 #undef SEED
-#undef DST_PTR
 #undef SRC_PTR
 #undef SRC_LEN
+#undef DST_PTR
 #undef TOTAL_LEN
 #undef MIX0
 #undef MIX1
@@ -515,31 +516,31 @@ combine:
 
 TEXT ·blocks256(SB),0,$0-48
 #define S_PTR DI
-	MOVQ     s_ptr+0(FP), S_PTR
+	MOVQ     s_base+0(FP), S_PTR
 #define SRC_PTR SI
-	MOVQ     src_ptr+24(FP), SRC_PTR
+	MOVQ     src_base+24(FP), SRC_PTR
 #define SRC_LEN AX
 	MOVQ     src_len+32(FP), SRC_LEN
-	VMOVDQU32 0(S_PTR), Y16
-	VMOVDQU32 32(S_PTR), Y17
-	VMOVDQU32 64(S_PTR), Y18
-	VMOVDQU32 96(S_PTR), Y19
-	VMOVDQU32 128(S_PTR), Y20
-	VMOVDQU32 160(S_PTR), Y21
-	VMOVDQU32 192(S_PTR), Y22
-	VMOVDQU32 224(S_PTR), Y23
+	VMOVDQU  0(S_PTR), Y8
+	VMOVDQU  32(S_PTR), Y9
+	VMOVDQU  64(S_PTR), Y10
+	VMOVDQU  96(S_PTR), Y11
+	VMOVDQU  128(S_PTR), Y12
+	VMOVDQU  160(S_PTR), Y13
+	VMOVDQU  192(S_PTR), Y14
+	VMOVDQU  224(S_PTR), Y15
 
 loop:
 	CMPQ     SRC_LEN, $256
 	JB       done
-	VAESDEC  0(SRC_PTR), Y16, Y16
-	VAESDEC  32(SRC_PTR), Y17, Y17
-	VAESDEC  64(SRC_PTR), Y18, Y18
-	VAESDEC  96(SRC_PTR), Y19, Y19
-	VAESDEC  128(SRC_PTR), Y20, Y20
-	VAESDEC  160(SRC_PTR), Y21, Y21
-	VAESDEC  192(SRC_PTR), Y22, Y22
-	VAESDEC  224(SRC_PTR), Y23, Y23
+	VAESDEC  0(SRC_PTR), Y8, Y8
+	VAESDEC  32(SRC_PTR), Y9, Y9
+	VAESDEC  64(SRC_PTR), Y10, Y10
+	VAESDEC  96(SRC_PTR), Y11, Y11
+	VAESDEC  128(SRC_PTR), Y12, Y12
+	VAESDEC  160(SRC_PTR), Y13, Y13
+	VAESDEC  192(SRC_PTR), Y14, Y14
+	VAESDEC  224(SRC_PTR), Y15, Y15
 
 	// Update source pointer.
 	ADDQ     $256, SRC_PTR
@@ -547,28 +548,29 @@ loop:
 	JMP      loop
 
 done:
-	VMOVDQU32 Y16, 0(S_PTR)
-	VMOVDQU32 Y17, 32(S_PTR)
-	VMOVDQU32 Y18, 64(S_PTR)
-	VMOVDQU32 Y19, 96(S_PTR)
-	VMOVDQU32 Y20, 128(S_PTR)
-	VMOVDQU32 Y21, 160(S_PTR)
-	VMOVDQU32 Y22, 192(S_PTR)
-	VMOVDQU32 Y23, 224(S_PTR)
+	VMOVDQU  Y8, 0(S_PTR)
+	VMOVDQU  Y9, 32(S_PTR)
+	VMOVDQU  Y10, 64(S_PTR)
+	VMOVDQU  Y11, 96(S_PTR)
+	VMOVDQU  Y12, 128(S_PTR)
+	VMOVDQU  Y13, 160(S_PTR)
+	VMOVDQU  Y14, 192(S_PTR)
+	VMOVDQU  Y15, 224(S_PTR)
+	VZEROUPPER
 	RET
 #undef S_PTR
 #undef SRC_PTR
 #undef SRC_LEN
 
-TEXT ·checksum512(SB),0,$32-56
+TEXT ·checksum512(SB),0,$32-48
 #define SEED R8
 	MOVQ     seed+0(FP), SEED
-#define DST_PTR DI
-	MOVQ     dst_ptr+8(FP), DST_PTR
 #define SRC_PTR SI
-	MOVQ     src_ptr+32(FP), SRC_PTR
+	MOVQ     src_base+8(FP), SRC_PTR
 #define SRC_LEN AX
-	MOVQ     src_len+40(FP), SRC_LEN
+	MOVQ     src_len+16(FP), SRC_LEN
+#define DST_PTR DI
+	LEAQ     ret+32(FP), DST_PTR
 
 	// Backup total input length.
 #define TOTAL_LEN R9
@@ -761,9 +763,9 @@ combine:
 	MOVOU    X7, 0(DST_PTR)
 	RET
 #undef SEED
-#undef DST_PTR
 #undef SRC_PTR
 #undef SRC_LEN
+#undef DST_PTR
 #undef TOTAL_LEN
 #undef MIX0
 #undef MIX1
@@ -773,9 +775,9 @@ combine:
 
 TEXT ·blocks512(SB),0,$0-48
 #define S_PTR DI
-	MOVQ     s_ptr+0(FP), S_PTR
+	MOVQ     s_base+0(FP), S_PTR
 #define SRC_PTR SI
-	MOVQ     src_ptr+24(FP), SRC_PTR
+	MOVQ     src_base+24(FP), SRC_PTR
 #define SRC_LEN AX
 	MOVQ     src_len+32(FP), SRC_LEN
 	VMOVDQU64 0(S_PTR), Z16
@@ -806,21 +808,21 @@ done:
 #undef SRC_PTR
 #undef SRC_LEN
 
-TEXT ·finish128(SB),0,$32-112
+TEXT ·finish128(SB),0,$32-104
 #define SEED R8
 	MOVQ     seed+0(FP), SEED
 #define S_PTR R9
-	MOVQ     s_ptr+8(FP), S_PTR
-#define DST_PTR DI
-	MOVQ     dst_ptr+32(FP), DST_PTR
+	MOVQ     s_base+8(FP), S_PTR
 #define SRC_PTR SI
-	MOVQ     src_ptr+56(FP), SRC_PTR
+	MOVQ     rem_base+32(FP), SRC_PTR
 #define SRC_LEN AX
-	MOVQ     src_len+64(FP), SRC_LEN
+	MOVQ     rem_len+40(FP), SRC_LEN
 #define TRAIL_PTR R10
-	MOVQ     trail_ptr+80(FP), TRAIL_PTR
+	MOVQ     trail_base+56(FP), TRAIL_PTR
 #define TOTAL_LEN BX
-	MOVQ     total_len+104(FP), TOTAL_LEN
+	MOVQ     length+80(FP), TOTAL_LEN
+#define DST_PTR DI
+	LEAQ     ret+88(FP), DST_PTR
 	MOVOU    0(S_PTR), X0
 	MOVOU    16(S_PTR), X1
 	MOVOU    32(S_PTR), X2
@@ -983,11 +985,11 @@ combine:
 	RET
 #undef SEED
 #undef S_PTR
-#undef DST_PTR
 #undef SRC_PTR
 #undef SRC_LEN
 #undef TRAIL_PTR
 #undef TOTAL_LEN
+#undef DST_PTR
 #undef MIX0
 #undef MIX1
 #undef PARTIAL_PTR
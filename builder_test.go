@@ -0,0 +1,73 @@
+package meow
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestBuilderEncoding(t *testing.T) {
+	b := NewBuilder(4)
+	b.AddUint64(0x0102030405060708)
+	b.AddBytes([]byte("key"))
+	b.AddString("")
+
+	var expect []byte
+	expect = append(expect, 'u', 8, 7, 6, 5, 4, 3, 2, 1)
+	expect = append(expect, 'b', 3, 0, 0, 0, 0, 0, 0, 0, 'k', 'e', 'y')
+	expect = append(expect, 'b', 0, 0, 0, 0, 0, 0, 0, 0)
+	hi, lo := b.Sum128()
+	if ehi, elo := Checksum128(4, expect); hi != ehi || lo != elo {
+		t.Fatalf("got=%016x:%016x expect=%016x:%016x", hi, lo, ehi, elo)
+	}
+
+	b.Reset()
+	hi, lo = b.Sum128()
+	if ehi, elo := Checksum128(4, nil); hi != ehi || lo != elo {
+		t.Fatalf("after Reset got=%016x:%016x expect=%016x:%016x", hi, lo, ehi, elo)
+	}
+}
+
+func TestBuilderDistinguishesFields(t *testing.T) {
+	var one [8]byte
+	binary.LittleEndian.PutUint64(one[:], 1)
+	sequences := map[string]func(b *Builder){
+		`{1, "a"}`:      func(b *Builder) { b.AddUint64(1); b.AddString("a") },
+		`{"", "1a"}`:    func(b *Builder) { b.AddString(""); b.AddString("1a") },
+		`{"1a"}`:        func(b *Builder) { b.AddString("1a") },
+		`{"1", "a"}`:    func(b *Builder) { b.AddString("1"); b.AddString("a") },
+		`{"a", 1}`:      func(b *Builder) { b.AddString("a"); b.AddUint64(1) },
+		`{le(1), "a"}`:  func(b *Builder) { b.AddBytes(one[:]); b.AddString("a") },
+		`{}`:            func(b *Builder) {},
+		`{""}`:          func(b *Builder) { b.AddString("") },
+		`{"", ""}`:      func(b *Builder) { b.AddString(""); b.AddString("") },
+		`{0}`:           func(b *Builder) { b.AddUint64(0) },
+		`{0, 0}`:        func(b *Builder) { b.AddUint64(0); b.AddUint64(0) },
+		`{1, "a", nil}`: func(b *Builder) { b.AddUint64(1); b.AddString("a"); b.AddBytes(nil) },
+	}
+	seen := map[Key128]string{}
+	for name, add := range sequences {
+		var b Builder
+		add(&b)
+		var k Key128
+		k.Hi, k.Lo = b.Sum128()
+		if prev, ok := seen[k]; ok {
+			t.Errorf("%s and %s collide", prev, name)
+		}
+		seen[k] = name
+	}
+}
+
+func TestBuilderAllocs(t *testing.T) {
+	b := NewBuilder(0)
+	key := []byte("composite")
+	allocs := testing.AllocsPerRun(100, func() {
+		b.Reset()
+		b.AddUint64(42)
+		b.AddBytes(key)
+		b.AddString("field")
+		b.Sum128()
+	})
+	if allocs != 0 {
+		t.Errorf("allocs=%v expect=0", allocs)
+	}
+}
@@ -0,0 +1,127 @@
+// Command meowsum prints or checks Meow checksums, in the style of sha256sum.
+//
+// Usage:
+//
+//	meowsum [-seed seed] [file ...]
+//	meowsum [-seed seed] -check [file ...]
+//
+// With no file, or when file is -, standard input is read. In check mode the
+// files contain lines of the form "<hex>  <filename>", as printed by meowsum,
+// and each listed file is verified.
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pckhoi/meow"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes meowsum with the given arguments and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("meowsum", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	seed := flags.Uint64("seed", 0, "hash seed")
+	check := flags.Bool("check", false, "read checksums from the files and check them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	c := &command{seed: *seed, stdin: stdin, stdout: stdout, stderr: stderr}
+	names := flags.Args()
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+
+	ok := true
+	for _, name := range names {
+		if *check {
+			ok = c.check(name) && ok
+		} else {
+			ok = c.print(name) && ok
+		}
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+type command struct {
+	seed   uint64
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// checksum computes the checksum of the named file, or standard input for "-".
+func (c *command) checksum(name string) ([meow.Size]byte, error) {
+	if name == "-" {
+		return meow.ChecksumReader(c.seed, c.stdin)
+	}
+	return meow.ChecksumFile(c.seed, name)
+}
+
+// print outputs the checksum line for the named file.
+func (c *command) print(name string) bool {
+	sum, err := c.checksum(name)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "meowsum: %v\n", err)
+		return false
+	}
+	fmt.Fprintf(c.stdout, "%x  %s\n", sum, name)
+	return true
+}
+
+// check verifies every checksum line in the named file.
+func (c *command) check(name string) bool {
+	var r io.Reader = c.stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "meowsum: %v\n", err)
+			return false
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ok := true
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.SplitN(s.Text(), "  ", 2)
+		expect, err := hex.DecodeString(fields[0])
+		if len(fields) != 2 || err != nil || len(expect) != meow.Size {
+			fmt.Fprintf(c.stderr, "meowsum: %s:%d: improperly formatted checksum line\n", name, line)
+			ok = false
+			continue
+		}
+
+		sum, err := c.checksum(fields[1])
+		switch {
+		case err != nil:
+			fmt.Fprintf(c.stderr, "meowsum: %v\n", err)
+			fmt.Fprintf(c.stdout, "%s: FAILED open or read\n", fields[1])
+			ok = false
+		case string(sum[:]) != string(expect):
+			fmt.Fprintf(c.stdout, "%s: FAILED\n", fields[1])
+			ok = false
+		default:
+			fmt.Fprintf(c.stdout, "%s: OK\n", fields[1])
+		}
+	}
+	if err := s.Err(); err != nil {
+		fmt.Fprintf(c.stderr, "meowsum: %v\n", err)
+		return false
+	}
+	return ok
+}
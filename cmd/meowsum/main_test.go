@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pckhoi/meow"
+)
+
+func TestPrint(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hello.txt")
+	if err := ioutil.WriteFile(path, []byte("Hello, World!"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("from stdin")
+	if status := run([]string{"-seed", "42", path, "-"}, stdin, &stdout, &stderr); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+
+	expect := fmt.Sprintf("%x  %s\n%x  -\n",
+		meow.Checksum(42, []byte("Hello, World!")), path,
+		meow.Checksum(42, []byte("from stdin")))
+	if stdout.String() != expect {
+		t.Fatalf("got:\n%s\nexpect:\n%s", stdout.String(), expect)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good")
+	bad := filepath.Join(dir, "bad")
+	for _, path := range []string{good, bad} {
+		if err := ioutil.WriteFile(path, []byte(path), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var list bytes.Buffer
+	if status := run([]string{good, bad}, nil, &list, ioutil.Discard); status != 0 {
+		t.Fatalf("exit status %d", status)
+	}
+
+	// Passing check.
+	var stdout bytes.Buffer
+	if status := run([]string{"-check"}, bytes.NewReader(list.Bytes()), &stdout, ioutil.Discard); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stdout.String())
+	}
+	if expect := good + ": OK\n" + bad + ": OK\n"; stdout.String() != expect {
+		t.Fatalf("got:\n%s\nexpect:\n%s", stdout.String(), expect)
+	}
+
+	// Modify a file and check again.
+	if err := ioutil.WriteFile(bad, []byte("modified"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if status := run([]string{"-check"}, bytes.NewReader(list.Bytes()), &stdout, ioutil.Discard); status != 1 {
+		t.Fatalf("exit status %d expect 1", status)
+	}
+	if expect := good + ": OK\n" + bad + ": FAILED\n"; stdout.String() != expect {
+		t.Fatalf("got:\n%s\nexpect:\n%s", stdout.String(), expect)
+	}
+
+	// Malformed lines.
+	var stderr bytes.Buffer
+	if status := run([]string{"-check"}, strings.NewReader("not a checksum line\n"), ioutil.Discard, &stderr); status != 1 {
+		t.Fatalf("exit status %d expect 1", status)
+	}
+	if !strings.Contains(stderr.String(), "improperly formatted") {
+		t.Fatalf("unexpected error output: %s", stderr.String())
+	}
+}
@@ -17,35 +17,66 @@ var cpu struct {
 
 func init() {
 	determineCPUFeatures()
+	accelerate()
+}
 
+// accelerate selects the fastest implementation supported by the CPU, and
+// reports whether one was found.
+func accelerate() bool {
 	switch {
 	case cpu.HasVAES && cpu.HasAVX512F && cpu.EnabledAVX512:
 		implementation = "vaes-512"
 		checksum = checksum512
 		blocks = blocks512
 		finish = finish128
+		finishAsm = true
+	case cpu.HasVAES && cpu.HasAVX && cpu.EnabledAVX:
+		// 256-bit VAES processes two streams per instruction, on CPUs with VAES
+		// but without usable AVX-512.
+		implementation = "vaes-256"
+		checksum = checksum256
+		blocks = blocks256
+		finish = finish128
+		finishAsm = true
 	case cpu.HasAES && cpu.HasAVX && cpu.EnabledAVX:
 		// AVX required for VEX-encoded AES instruction, which allows non-aligned memory addresses.
 		implementation = "aes-ni"
 		checksum = checksum128
 		blocks = blocks128
 		finish = finish128
+		finishAsm = true
+	default:
+		return false
 	}
+	return true
 }
 
 // AES-NI implementation.
-func checksum128(seed uint64, dst, src []byte)
+func checksum128(seed uint64, src []byte) [Size]byte
 func blocks128(s, src []byte)
-func finish128(seed uint64, s, dst, rem, trail []byte, length uint64)
+
+//go:noescape
+func finish128(seed uint64, s, rem, trail []byte, length uint64) [Size]byte
 
 // VAES-256 implementation.
-func checksum256(seed uint64, dst, src []byte)
+func checksum256(seed uint64, src []byte) [Size]byte
 func blocks256(s, src []byte)
 
 // VAES-512 implementation.
-func checksum512(seed uint64, dst, src []byte)
+func checksum512(seed uint64, src []byte) [Size]byte
 func blocks512(s, src []byte)
 
+// finishDirect is equivalent to finish, for callers whose data may be stack
+// allocated. Calling the implementation directly rather than through the
+// finish variable lets escape analysis see that the arguments are not
+// retained.
+func finishDirect(seed uint64, s, rem, trail []byte, length uint64) [Size]byte {
+	if finishAsm {
+		return finish128(seed, s, rem, trail, length)
+	}
+	return finishgo(seed, s, rem, trail, length)
+}
+
 // determineCPUFeatures populates flags in global cpu variable by querying CPUID.
 func determineCPUFeatures() {
 	maxID, _, _, _ := cpuid(0, 0)
@@ -3,7 +3,9 @@
 package meow
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"testing"
 )
 
@@ -15,3 +17,87 @@ func TestDisplayCPUFeatures(t *testing.T) {
 	}
 	t.Log(string(b))
 }
+
+// backend is an assembly implementation of Meow.
+type backend struct {
+	Name      string
+	Supported bool
+	Checksum  func(seed uint64, src []byte) [Size]byte
+	Blocks    func(s, src []byte)
+}
+
+// backends returns all assembly implementations, and whether they are supported on this CPU.
+func backends() []backend {
+	return []backend{
+		{"vaes-512", cpu.HasVAES && cpu.HasAVX512F && cpu.EnabledAVX512, checksum512, blocks512},
+		{"vaes-256", cpu.HasVAES && cpu.HasAVX && cpu.EnabledAVX, checksum256, blocks256},
+		{"aes-ni", cpu.HasAES && cpu.HasAVX && cpu.EnabledAVX, checksum128, blocks128},
+	}
+}
+
+func TestBackends(t *testing.T) {
+	for _, b := range backends() {
+		b := b
+		t.Run(b.Name, func(t *testing.T) {
+			if !b.Supported {
+				t.Skip("not supported on this CPU")
+			}
+			CheckEqual(t, checksumPureGo, func(seed uint64, data []byte) []byte {
+				cksum := b.Checksum(seed, data)
+				return cksum[:]
+			})
+		})
+	}
+}
+
+// TestAccelerateWithoutAVX512 hides AVX-512 from accelerate, as on CPUs that
+// have VAES but no AVX-512, and checks the VAES-256 backend is selected.
+func TestAccelerateWithoutAVX512(t *testing.T) {
+	if !(cpu.HasVAES && cpu.HasAVX && cpu.EnabledAVX) {
+		t.Skip("VAES not supported on this CPU")
+	}
+	saved := cpu
+	defer func() {
+		cpu = saved
+		UseAccelerated()
+	}()
+
+	cpu.HasAVX512F = false
+	cpu.EnabledAVX512 = false
+	if !UseAccelerated() || Implementation() != "vaes-256" {
+		t.Fatalf("implementation=%s expect=vaes-256", Implementation())
+	}
+	CheckEqual(t, checksumSlice, checksumPureGo)
+}
+
+func TestBackendsEmptyInput(t *testing.T) {
+	for _, b := range backends() {
+		if !b.Supported {
+			continue
+		}
+		for _, v := range emptyVectors {
+			got := b.Checksum(v.Seed, nil)
+			if s := hex.EncodeToString(got[:]); s != v.Hash {
+				t.Errorf("%s seed=%016x got=%s expect=%s", b.Name, v.Seed, s, v.Hash)
+			}
+		}
+	}
+}
+
+func BenchmarkBackendBlocks(b *testing.B) {
+	var s [BlockSize]byte
+	data := make([]byte, 1<<20)
+	for _, be := range backends() {
+		be := be
+		name := fmt.Sprintf("impl=%s", be.Name)
+		b.Run(name, func(b *testing.B) {
+			if !be.Supported {
+				b.Skip("not supported on this CPU")
+			}
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				be.Blocks(s[:], data)
+			}
+		})
+	}
+}
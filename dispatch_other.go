@@ -0,0 +1,15 @@
+//go:build !amd64 || noasm
+// +build !amd64 noasm
+
+package meow
+
+// accelerate reports that no accelerated implementation is available.
+func accelerate() bool {
+	return false
+}
+
+// finishDirect is equivalent to finish, for callers whose data may be stack
+// allocated.
+func finishDirect(seed uint64, s, rem, trail []byte, length uint64) [Size]byte {
+	return finishgo(seed, s, rem, trail, length)
+}
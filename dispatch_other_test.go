@@ -0,0 +1,15 @@
+//go:build !amd64 || noasm
+// +build !amd64 noasm
+
+package meow
+
+import "testing"
+
+func TestPureGoSelected(t *testing.T) {
+	if Implementation() != "go" {
+		t.Fatalf("implementation=%s expect=go", Implementation())
+	}
+	if UseAccelerated() {
+		t.Fatal("UseAccelerated reported an accelerated implementation")
+	}
+}
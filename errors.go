@@ -0,0 +1,30 @@
+package meow
+
+import "os"
+
+// Error records a failure in one of the file or reader helpers, such as
+// ChecksumFile or ChecksumReader, and the operation that caused it.
+type Error struct {
+	Op   string // "open", "stat", "read" or "walk"
+	Path string // file path, or empty for readers
+	Err  error  // underlying error
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return "meow: " + e.Op + ": " + e.Err.Error()
+	}
+	return "meow: " + e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error, for use with errors.Is and errors.As.
+func (e *Error) Unwrap() error { return e.Err }
+
+// fileError returns an Error for op on path. The path is not repeated if err
+// is an *os.PathError.
+func fileError(op, path string, err error) *Error {
+	if pe, ok := err.(*os.PathError); ok {
+		err = pe.Err
+	}
+	return &Error{Op: op, Path: path, Err: err}
+}
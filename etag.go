@@ -0,0 +1,30 @@
+package meow
+
+import (
+	"encoding/hex"
+	"io"
+)
+
+// ETag returns a strong HTTP entity tag for data: the quoted lowercase hex
+// encoding of its 128-bit Meow checksum, suitable for the ETag header.
+func ETag(seed uint64, data []byte) string {
+	return etag(checksumOneShot(seed, data))
+}
+
+// ETagReader is like ETag, but hashes all data read from r until EOF. It
+// returns any error other than io.EOF encountered while reading.
+func ETagReader(seed uint64, r io.Reader) (string, error) {
+	sum, err := ChecksumReader(seed, r)
+	if err != nil {
+		return "", err
+	}
+	return etag(sum), nil
+}
+
+func etag(sum [Size]byte) string {
+	var b [2 + 2*Size]byte
+	b[0] = '"'
+	hex.Encode(b[1:], sum[:])
+	b[len(b)-1] = '"'
+	return string(b[:])
+}
@@ -0,0 +1,40 @@
+package meow
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestETag(t *testing.T) {
+	data := []byte("Hello, World!")
+	tag := ETag(0, data)
+	if tag != `"a8cfb4aad7eada8ef007aafe27135386"` {
+		t.Fatalf("got=%s", tag)
+	}
+	if len(tag) != 2+2*Size || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		t.Fatalf("malformed tag %s", tag)
+	}
+
+	if other := ETag(0, []byte("Hello, World!")); other != tag {
+		t.Fatalf("identical payloads: %s != %s", other, tag)
+	}
+	if other := ETag(0, []byte("Hello, World?")); other == tag {
+		t.Fatalf("different payloads have the same tag %s", tag)
+	}
+
+	got, err := ETagReader(0, iotest.OneByteReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != tag {
+		t.Fatalf("ETagReader got=%s expect=%s", got, tag)
+	}
+
+	errRead := errors.New("read failed")
+	if _, err := ETagReader(0, iotest.ErrReader(errRead)); !errors.Is(err, errRead) {
+		t.Fatalf("got error %v expect %v", err, errRead)
+	}
+}
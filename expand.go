@@ -0,0 +1,24 @@
+package meow
+
+import "encoding/binary"
+
+// Expand fills out with an arbitrary length output derived from seed and data.
+// Block i of the output is the Meow checksum of data followed by the 8-byte
+// little-endian encoding of i, starting from 0, and the last block is
+// truncated to the length of out.
+//
+// Expand is a construction on top of Meow hash, not part of the Meow
+// specification. Like Meow itself, it is not cryptographically secure.
+func Expand(seed uint64, data []byte, out []byte) {
+	d := New(seed)
+	d.Write(data)
+
+	var counter [8]byte
+	for i := uint64(0); len(out) > 0; i++ {
+		c := *d
+		binary.LittleEndian.PutUint64(counter[:], i)
+		c.Write(counter[:])
+		sum := c.sum()
+		out = out[copy(out, sum[:]):]
+	}
+}
@@ -0,0 +1,36 @@
+package meow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func TestExpandKnownAnswer(t *testing.T) {
+	out := make([]byte, 48)
+	Expand(7, []byte("subkey material"), out)
+	expect := "c6bf5bd303d8987a521a981be8ffcae5377544500454628a2c7c69f8055242e5df3224c6f74999058b93f44885b1ed78"
+	if got := hex.EncodeToString(out); got != expect {
+		t.Fatalf("got=%s expect=%s", got, expect)
+	}
+}
+
+func TestExpandConstruction(t *testing.T) {
+	data := []byte("Expand concatenates checksums of data and a counter")
+	var expect []byte
+	for i := uint64(0); i < 4; i++ {
+		var counter [8]byte
+		binary.LittleEndian.PutUint64(counter[:], i)
+		sum := Checksum(3, append(append([]byte{}, data...), counter[:]...))
+		expect = append(expect, sum[:]...)
+	}
+
+	for _, n := range []int{0, 1, 16, 17, 40, 64} {
+		out := make([]byte, n)
+		Expand(3, data, out)
+		if !bytes.Equal(out, expect[:n]) {
+			t.Errorf("length %d: got=%x expect=%x", n, out, expect[:n])
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package meow
+
+import "os"
+
+// ChecksumFile returns the Meow checksum of the contents of the named file.
+// Errors are of type *Error.
+func ChecksumFile(seed uint64, path string) ([Size]byte, error) {
+	var sum [Size]byte
+	f, err := os.Open(path)
+	if err != nil {
+		return sum, fileError("open", path, err)
+	}
+	defer f.Close()
+	return checksumOpenFile(seed, path, f)
+}
+
+// checksumOpenFile returns the Meow checksum of the remaining contents of f,
+// which was opened from path.
+func checksumOpenFile(seed uint64, path string, f *os.File) ([Size]byte, error) {
+	d := New(seed)
+	if _, err := d.ReadFrom(f); err != nil {
+		return [Size]byte{}, fileError("read", path, err)
+	}
+	return d.sum(), nil
+}
+
+// MustChecksumFile is like ChecksumFile but panics if the file cannot be read.
+// It simplifies safe initialization of global variables from bundled files.
+func MustChecksumFile(seed uint64, path string) [Size]byte {
+	sum, err := ChecksumFile(seed, path)
+	if err != nil {
+		panic(err)
+	}
+	return sum
+}
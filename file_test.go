@@ -0,0 +1,77 @@
+package meow
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, n := range []int{0, 37, 3*readSize + 5} {
+		data := make([]byte, n)
+		rand.Read(data)
+		path := filepath.Join(dir, "data")
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ChecksumFile(1, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expect := Checksum(1, data); got != expect {
+			t.Errorf("length %d: got=%x expect=%x", n, got, expect)
+		}
+	}
+}
+
+func TestChecksumFileNotExist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	_, err := ChecksumFile(0, path)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got error %v expect not exist", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "open" || e.Path != path {
+		t.Fatalf("got error %#v expect *Error with Op open", err)
+	}
+	if expect := "meow: open " + path + ": " + e.Err.Error(); err.Error() != expect {
+		t.Fatalf("got message %q expect %q", err.Error(), expect)
+	}
+}
+
+func TestChecksumFileReadError(t *testing.T) {
+	// Directories can be opened but not read on most platforms.
+	dir := t.TempDir()
+	_, err := ChecksumFile(0, dir)
+	if err == nil {
+		t.Skip("directory read succeeded on this platform")
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "read" || e.Path != dir {
+		t.Fatalf("got error %#v expect *Error with Op read", err)
+	}
+}
+
+func TestMustChecksumFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	data := []byte("bundled asset")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, expect := MustChecksumFile(1, path), Checksum(1, data); got != expect {
+		t.Fatalf("got=%x expect=%x", got, expect)
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("expected panic with not exist error, got %v", err)
+		}
+	}()
+	MustChecksumFile(1, filepath.Join(t.TempDir(), "missing"))
+}
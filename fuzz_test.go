@@ -0,0 +1,60 @@
+//go:build go1.18
+// +build go1.18
+
+// Fuzz targets need testing.F, which is new in Go 1.18. go.mod declares Go
+// 1.17, so they are built separately to keep the rest of the package tests
+// building on older toolchains.
+
+package meow
+
+import (
+	"bytes"
+	"testing"
+)
+
+// addSeedCorpus adds inputs of lengths around the AES and Meow block sizes.
+func addSeedCorpus(f *testing.F, extra ...interface{}) {
+	for _, n := range []int{0, 1, 15, 16, 17, 255, 256, 257} {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		f.Add(append([]interface{}{uint64(n), data}, extra...)...)
+	}
+}
+
+func FuzzChecksumEquivalence(f *testing.F) {
+	addSeedCorpus(f, uint(7))
+	f.Fuzz(func(t *testing.T, seed uint64, data []byte, split uint) {
+		expect := Checksum(seed, data)
+		i := int(split % uint(len(data)+1))
+
+		h := New(seed)
+		h.Write(data[:i])
+		h.Write(data[i:])
+		if got := h.Sum(nil); !bytes.Equal(got, expect[:]) {
+			t.Fatalf("split at %d: got=%x expect=%x", i, got, expect)
+		}
+	})
+}
+
+func FuzzBackendEquivalence(f *testing.F) {
+	addSeedCorpus(f)
+	f.Fuzz(func(t *testing.T, seed uint64, data []byte) {
+		expect := checksumgo(seed, data)
+		if got := checksum(seed, data); got != expect {
+			t.Fatalf("implementation %s: got=%x expect=%x", implementation, got, expect)
+		}
+	})
+}
+
+func FuzzBlocksGo(f *testing.F) {
+	f.Add(make([]byte, BlockSize), make([]byte, BlockSize))
+	f.Add(bytes.Repeat([]byte{0xff}, BlockSize), bytes.Repeat([]byte{0x5a}, 3*BlockSize))
+	f.Fuzz(func(t *testing.T, s, data []byte) {
+		if len(s) < BlockSize {
+			return
+		}
+		checkBlocks(t, s[:BlockSize], data)
+	})
+}
@@ -0,0 +1,263 @@
+package meow
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// readSize is the size of buffers used for reading into a Digest.
+const readSize = 32 * BlockSize
+
+// readBuffers is a pool of buffers for reading into a Digest.
+var readBuffers = sync.Pool{
+	New: func() interface{} { return &[readSize]byte{} },
+}
+
+// getReadBuffer returns a buffer from the readBuffers pool.
+func getReadBuffer() *[readSize]byte {
+	return readBuffers.Get().(*[readSize]byte)
+}
+
+// readFrom adds data read from r to the hash until EOF, reading into buf. If
+// after is not nil it is called following each read with the total number of
+// bytes read so far, and reading stops if it returns an error.
+func (d *Digest) readFrom(r io.Reader, buf []byte, after func(total int64) error) (int64, error) {
+	var total int64
+	for {
+		n, err := r.Read(buf)
+		d.Write(buf[:n])
+		total += int64(n)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		if after != nil {
+			if err := after(total); err != nil {
+				return total, err
+			}
+		}
+	}
+}
+
+// WriteChan adds every chunk received from ch to the running hash, until ch is
+// closed, and returns the total number of bytes written. Like Write, it does
+// not retain the chunks, so senders may reuse their buffers once the next
+// chunk has been received. It never returns an error.
+func (d *Digest) WriteChan(ch <-chan []byte) (int64, error) {
+	var total int64
+	for p := range ch {
+		d.Write(p)
+		total += int64(len(p))
+	}
+	return total, nil
+}
+
+// ChecksumReader returns the Meow checksum of all data read from r until EOF.
+// It returns any error other than io.EOF encountered while reading, wrapped in
+// an *Error with Op "read".
+func ChecksumReader(seed uint64, r io.Reader) ([Size]byte, error) {
+	var sum [Size]byte
+	d := New(seed)
+	if _, err := d.ReadFrom(r); err != nil {
+		return sum, &Error{Op: "read", Err: err}
+	}
+	return d.sum(), nil
+}
+
+// ChecksumReaderBuffered is like ChecksumReader, but reads into a buffer of
+// bufSize bytes, rounded up to a multiple of BlockSize. Large buffers reduce
+// the number of reads from fast sources, and small ones bound memory use.
+// ChecksumReader uses a buffer of 32 blocks. It panics if bufSize is not
+// positive.
+func ChecksumReaderBuffered(seed uint64, r io.Reader, bufSize int) ([Size]byte, error) {
+	if bufSize <= 0 {
+		panic("meow: ChecksumReaderBuffered buffer size must be positive")
+	}
+	bufSize = (bufSize + BlockSize - 1) &^ (BlockSize - 1)
+
+	var buf []byte
+	if bufSize == readSize {
+		p := getReadBuffer()
+		defer readBuffers.Put(p)
+		buf = p[:]
+	} else {
+		buf = make([]byte, bufSize)
+	}
+
+	var sum [Size]byte
+	d := New(seed)
+	if _, err := d.readFrom(r, buf, nil); err != nil {
+		return sum, &Error{Op: "read", Err: err}
+	}
+	return d.sum(), nil
+}
+
+// ChecksumReaderContext is like ChecksumReader, but stops and returns the
+// context error, unwrapped, if ctx is done. The context is checked between
+// reads, so a blocked read is not interrupted.
+func ChecksumReaderContext(ctx context.Context, seed uint64, r io.Reader) ([Size]byte, error) {
+	var sum [Size]byte
+	if err := ctx.Err(); err != nil {
+		return sum, err
+	}
+
+	p := getReadBuffer()
+	defer readBuffers.Put(p)
+	d := New(seed)
+	if _, err := d.readFrom(r, p[:], func(int64) error { return ctx.Err() }); err != nil {
+		if err == ctx.Err() {
+			return sum, err
+		}
+		return sum, &Error{Op: "read", Err: err}
+	}
+	return d.sum(), nil
+}
+
+// DefaultProgressInterval is the default number of bytes between calls to the
+// ChecksumReaderProgress callback.
+const DefaultProgressInterval = 256 << 10
+
+// ChecksumReaderProgress is like ChecksumReader, but calls cb with the number
+// of bytes read so far each time at least interval more bytes have been
+// hashed, and at EOF unless that count was just reported. If interval is not
+// positive DefaultProgressInterval is used. cb may be nil.
+func ChecksumReaderProgress(seed uint64, r io.Reader, interval int64, cb func(bytesSoFar int64)) ([Size]byte, error) {
+	if cb == nil {
+		return ChecksumReader(seed, r)
+	}
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+
+	var sum [Size]byte
+	p := getReadBuffer()
+	defer readBuffers.Put(p)
+	d := New(seed)
+	next := interval
+	reported := int64(-1)
+	total, err := d.readFrom(r, p[:], func(total int64) error {
+		if total >= next {
+			cb(total)
+			reported = total
+			next = total + interval
+		}
+		return nil
+	})
+	if err != nil {
+		return sum, &Error{Op: "read", Err: err}
+	}
+	if total != reported {
+		cb(total)
+	}
+	return d.sum(), nil
+}
+
+// Reader computes the Meow checksum of data read through it.
+type Reader struct {
+	r io.Reader
+	d *Digest
+}
+
+// NewReader returns a Reader that reads from r, hashing all data read with the
+// given seed.
+func NewReader(seed uint64, r io.Reader) *Reader {
+	return &Reader{r: r, d: New(seed)}
+}
+
+// Read reads from the underlying reader, adding the bytes read to the hash. It
+// returns the byte count and error of the underlying reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.d.Write(p[:n])
+	return n, err
+}
+
+// Sum appends the checksum of the data read so far to b and returns the
+// resulting slice.
+func (r *Reader) Sum(b []byte) []byte {
+	return r.d.Sum(b)
+}
+
+// Sum128 returns the checksum of the data read so far, as in Digest.Sum128.
+func (r *Reader) Sum128() (hi, lo uint64) {
+	return r.d.Sum128()
+}
+
+// Writer computes the Meow checksum of data written through it.
+type Writer struct {
+	w io.Writer
+	d *Digest
+}
+
+// NewWriter returns a Writer that writes to w, hashing all data written with
+// the given seed.
+func NewWriter(seed uint64, w io.Writer) *Writer {
+	return &Writer{w: w, d: New(seed)}
+}
+
+// Write writes p to the underlying writer. Only the bytes accepted by the
+// underlying writer are added to the hash. A short write is reported as
+// io.ErrShortWrite if the underlying writer returned no error. A count
+// outside [0, len(p)] from a misbehaving underlying writer is clamped to that
+// range.
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if n < 0 {
+		n = 0
+	} else if n > len(p) {
+		n = len(p)
+	}
+	w.d.Write(p[:n])
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+// Sum appends the checksum of the data written so far to b and returns the
+// resulting slice.
+func (w *Writer) Sum(b []byte) []byte {
+	return w.d.Sum(b)
+}
+
+// Sum128 returns the checksum of the data written so far, as in
+// Digest.Sum128.
+func (w *Writer) Sum128() (hi, lo uint64) {
+	return w.d.Sum128()
+}
+
+// CountingWriter is an io.Writer that computes the Meow checksum and length of
+// everything written to it, without storing the data.
+type CountingWriter struct {
+	d *Digest
+}
+
+// NewCountingWriter returns a CountingWriter hashing with the given seed.
+func NewCountingWriter(seed uint64) *CountingWriter {
+	return &CountingWriter{d: New(seed)}
+}
+
+// Write adds p to the hash and count. It never returns an error.
+func (w *CountingWriter) Write(p []byte) (int, error) {
+	return w.d.Write(p)
+}
+
+// Count returns the number of bytes written so far.
+func (w *CountingWriter) Count() int64 {
+	return int64(w.d.BytesWritten())
+}
+
+// Sum appends the checksum of the data written so far to b and returns the
+// resulting slice.
+func (w *CountingWriter) Sum(b []byte) []byte {
+	return w.d.Sum(b)
+}
+
+// Sum128 returns the checksum of the data written so far, as in
+// Digest.Sum128.
+func (w *CountingWriter) Sum128() (hi, lo uint64) {
+	return w.d.Sum128()
+}
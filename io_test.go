@@ -0,0 +1,361 @@
+package meow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+	"testing/iotest"
+)
+
+func TestChecksumReader(t *testing.T) {
+	for _, n := range []int{0, 1, 255, 256, 257, readSize + 1, 1 << 20} {
+		data := make([]byte, n)
+		rand.Read(data)
+		expect := Checksum(42, data)
+
+		got, err := ChecksumReader(42, iotest.HalfReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expect {
+			t.Errorf("length %d: got=%x expect=%x", n, got, expect)
+		}
+	}
+}
+
+func TestChecksumReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 1000)), iotest.ErrReader(errRead))
+	_, err := ChecksumReader(0, r)
+	if !errors.Is(err, errRead) {
+		t.Fatalf("got error %v expect %v", err, errRead)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "read" || e.Path != "" {
+		t.Fatalf("got error %#v expect *Error with Op read", err)
+	}
+	if err.Error() != "meow: read: read failed" {
+		t.Fatalf("got message %q", err.Error())
+	}
+
+	for name, f := range map[string]func(io.Reader) error{
+		"ChecksumReaderBuffered": func(r io.Reader) error { _, err := ChecksumReaderBuffered(0, r, 100); return err },
+		"ChecksumReaderContext":  func(r io.Reader) error { _, err := ChecksumReaderContext(context.Background(), 0, r); return err },
+		"ChecksumReaderProgress": func(r io.Reader) error { _, err := ChecksumReaderProgress(0, r, 10, func(int64) {}); return err },
+	} {
+		err := f(iotest.ErrReader(errRead))
+		if !errors.As(err, &e) || e.Op != "read" || !errors.Is(err, errRead) {
+			t.Errorf("%s: got error %v", name, err)
+		}
+	}
+}
+
+func TestWriteChan(t *testing.T) {
+	data := make([]byte, 5*BlockSize+33)
+	rand.Read(data)
+
+	// The sender reuses two buffers, so chunks must not be retained.
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		var bufs [2][2 * BlockSize]byte
+		for i, p := 0, data; len(p) > 0; i++ {
+			n := rand.Intn(len(bufs[0]) + 1)
+			if n > len(p) {
+				n = len(p)
+			}
+			buf := bufs[i%2][:n]
+			copy(buf, p)
+			ch <- buf
+			p = p[n:]
+		}
+		ch <- nil
+	}()
+
+	h := New(5)
+	n, err := h.WriteChan(ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("wrote %d bytes expect %d", n, len(data))
+	}
+	expect := Checksum(5, data)
+	AssertBytesEqual(t, expect[:], h.Sum(nil))
+}
+
+func TestChecksumReaderBuffered(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.Read(data)
+	expect := Checksum(42, data)
+	for _, size := range []int{1, 100, BlockSize, BlockSize + 1, 4096, readSize, 1 << 20} {
+		got, err := ChecksumReaderBuffered(42, bytes.NewReader(data), size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expect {
+			t.Errorf("buffer size %d: got=%x expect=%x", size, got, expect)
+		}
+	}
+
+	for _, size := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("buffer size %d: expected panic", size)
+				}
+			}()
+			ChecksumReaderBuffered(42, bytes.NewReader(data), size)
+		}()
+	}
+}
+
+func TestReader(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.Read(data)
+	expect := Checksum(5, data)
+
+	r := NewReader(5, iotest.OneByteReader(bytes.NewReader(data)))
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("data was modified")
+	}
+	AssertBytesEqual(t, expect[:], r.Sum(nil))
+
+	r = NewReader(5, bytes.NewReader(data))
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	AssertBytesEqual(t, expect[:], r.Sum(nil))
+	hi, lo := r.Sum128()
+	if expectHi, expectLo := Checksum128(5, data); hi != expectHi || lo != expectLo {
+		t.Fatalf("Sum128() got=%016x:%016x expect=%016x:%016x", hi, lo, expectHi, expectLo)
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := NewReader(0, iotest.DataErrReader(iotest.ErrReader(errRead)))
+	if n, err := r.Read(make([]byte, 10)); n != 0 || err != errRead {
+		t.Fatalf("Read()=%d, %v expect 0, %v", n, err, errRead)
+	}
+
+	r = NewReader(0, iotest.DataErrReader(bytes.NewReader([]byte("hello"))))
+	n, err := r.Read(make([]byte, 10))
+	if n != 5 || err != io.EOF {
+		t.Fatalf("Read()=%d, %v expect 5, EOF", n, err)
+	}
+	expect := Checksum(0, []byte("hello"))
+	AssertBytesEqual(t, expect[:], r.Sum(nil))
+}
+
+// shortWriter accepts at most n bytes per write, without error.
+type shortWriter struct {
+	n int
+}
+
+func (w shortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return w.n, nil
+	}
+	return len(p), nil
+}
+
+func TestWriter(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.Read(data)
+	expect := Checksum(5, data)
+
+	var buf bytes.Buffer
+	w := NewWriter(5, &buf)
+	if _, err := io.Copy(w, iotest.HalfReader(bytes.NewReader(data))); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("data was modified")
+	}
+	AssertBytesEqual(t, expect[:], w.Sum(nil))
+	hi, lo := w.Sum128()
+	if expectHi, expectLo := Checksum128(5, data); hi != expectHi || lo != expectLo {
+		t.Fatalf("Sum128() got=%016x:%016x expect=%016x:%016x", hi, lo, expectHi, expectLo)
+	}
+}
+
+func TestWriterShortWrite(t *testing.T) {
+	w := NewWriter(0, shortWriter{n: 3})
+	n, err := w.Write([]byte("hello"))
+	if n != 3 || err != io.ErrShortWrite {
+		t.Fatalf("Write()=%d, %v expect 3, %v", n, err, io.ErrShortWrite)
+	}
+	expect := Checksum(0, []byte("hel"))
+	AssertBytesEqual(t, expect[:], w.Sum(nil))
+}
+
+// invalidWriter returns a fixed count from every write, regardless of the
+// length written.
+type invalidWriter int
+
+func (w invalidWriter) Write(p []byte) (int, error) {
+	return int(w), nil
+}
+
+func TestWriterInvalidWrite(t *testing.T) {
+	p := []byte("hello")
+	for _, c := range []struct {
+		N      int
+		Expect int
+	}{
+		{-1, 0},
+		{len(p) + 1, len(p)},
+	} {
+		w := NewWriter(0, invalidWriter(c.N))
+		if n, _ := w.Write(p); n != c.Expect {
+			t.Errorf("underlying n=%d: Write()=%d expect=%d", c.N, n, c.Expect)
+		}
+		expect := Checksum(0, p[:c.Expect])
+		AssertBytesEqual(t, expect[:], w.Sum(nil))
+	}
+}
+
+// chanReader returns one chunk per read, blocking until it is received.
+type chanReader <-chan []byte
+
+func (r chanReader) Read(p []byte) (int, error) {
+	chunk, ok := <-r
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(p, chunk), nil
+}
+
+func TestCountingWriter(t *testing.T) {
+	data := make([]byte, 4*BlockSize+77)
+	rand.Read(data)
+
+	w := NewCountingWriter(8)
+	var written int
+	for _, n := range []int{0, 1, 15, 16, 200, BlockSize, 2 * BlockSize} {
+		if _, err := w.Write(data[written : written+n]); err != nil {
+			t.Fatal(err)
+		}
+		written += n
+		if w.Count() != int64(written) {
+			t.Fatalf("Count()=%d expect=%d", w.Count(), written)
+		}
+	}
+	if _, err := io.Copy(w, bytes.NewReader(data[written:])); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Count() != int64(len(data)) {
+		t.Fatalf("Count()=%d expect=%d", w.Count(), len(data))
+	}
+	expect := Checksum(8, data)
+	AssertBytesEqual(t, expect[:], w.Sum(nil))
+	hi, lo := w.Sum128()
+	if ehi, elo := Checksum128(8, data); hi != ehi || lo != elo {
+		t.Fatalf("Sum128()=%016x:%016x expect=%016x:%016x", hi, lo, ehi, elo)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		w.Write(data[:100])
+	})
+	if allocs != 0 {
+		t.Errorf("Write allocs=%v expect=0", allocs)
+	}
+}
+
+func TestChecksumReaderContext(t *testing.T) {
+	data := make([]byte, 100000)
+	rand.Read(data)
+	sum, err := ChecksumReaderContext(context.Background(), 9, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := Checksum(9, data); sum != expect {
+		t.Fatalf("got=%x expect=%x", sum, expect)
+	}
+}
+
+func TestChecksumReaderContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks := make(chan []byte)
+	errc := make(chan error)
+	go func() {
+		_, err := ChecksumReaderContext(ctx, 0, chanReader(chunks))
+		errc <- err
+	}()
+
+	// Cancel once the first chunk has been read. The second chunk is only
+	// consumed if cancellation was not observed before the next read.
+	chunks <- []byte("first chunk")
+	cancel()
+	var err error
+	select {
+	case chunks <- []byte("second chunk"):
+		err = <-errc
+	case err = <-errc:
+	}
+
+	if err != context.Canceled {
+		t.Fatalf("got error %v expect %v", err, context.Canceled)
+	}
+}
+
+func TestChecksumReaderProgress(t *testing.T) {
+	data := make([]byte, 3*DefaultProgressInterval+5)
+	rand.Read(data)
+	expect := Checksum(1, data)
+
+	for _, interval := range []int64{0, 1000, readSize, 1 << 30} {
+		checkProgress(t, data, interval)
+	}
+	// The last read crosses the interval, and EOF comes on a read of its own.
+	checkProgress(t, data[:20000], 1000)
+	checkProgress(t, nil, 1000)
+
+	sum, err := ChecksumReaderProgress(1, bytes.NewReader(data), 0, nil)
+	if err != nil || sum != expect {
+		t.Fatalf("nil callback: got=%x, %v expect=%x", sum, err, expect)
+	}
+}
+
+// checkProgress confirms the progress reported while hashing data.
+func checkProgress(t *testing.T, data []byte, interval int64) {
+	t.Helper()
+	expect := Checksum(1, data)
+	var calls []int64
+	sum, err := ChecksumReaderProgress(1, bytes.NewReader(data), interval, func(n int64) {
+		calls = append(calls, n)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != expect {
+		t.Fatalf("interval %d: got=%x expect=%x", interval, sum, expect)
+	}
+
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	if len(calls) == 0 || calls[len(calls)-1] != int64(len(data)) {
+		t.Fatalf("interval %d: final progress %v expect %d", interval, calls, len(data))
+	}
+	for i := 1; i < len(calls); i++ {
+		// Only the final call, at EOF, may follow the previous one by less
+		// than interval.
+		if d := calls[i] - calls[i-1]; d <= 0 || (d < interval && i < len(calls)-1) {
+			t.Fatalf("interval %d: progress %d after %d", interval, calls[i], calls[i-1])
+		}
+	}
+	if max := int64(len(data))/interval + 1; int64(len(calls)) > max {
+		t.Fatalf("interval %d: %d calls expect at most %d", interval, len(calls), max)
+	}
+}
@@ -0,0 +1,80 @@
+package meow
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+)
+
+// zeroStreams is the initial state of the streams. It must not be modified.
+var zeroStreams [BlockSize]byte
+
+// ChecksumUint64 returns the 64-bit checksum of the little-endian encoding of
+// x. It is equivalent to Checksum64 on the 8-byte encoding, but faster.
+func ChecksumUint64(seed, x uint64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], x)
+	sum := finishDirect(seed, zeroStreams[:], b[:], b[:], uint64(len(b)))
+	return binary.LittleEndian.Uint64(sum[:8])
+}
+
+// ChecksumBytes16 returns the 64-bit checksum of a 16-byte key. It is
+// equivalent to Checksum64 on key[:], but faster.
+func ChecksumBytes16(seed uint64, key [aes.BlockSize]byte) uint64 {
+	sum := finishDirect(seed, zeroStreams[:], key[:], key[:], uint64(len(key)))
+	return binary.LittleEndian.Uint64(sum[:8])
+}
+
+// deriveSeedKey is the seed used to hash namespaces in DeriveSeed. It is the
+// ASCII encoding of "meowseed", and must never change.
+const deriveSeedKey = 0x6d656f7773656564
+
+// DeriveSeed returns a seed for the given namespace, allowing independent
+// families of hashes to be identified by name. It is deterministic, and stable
+// across releases of this package.
+func DeriveSeed(namespace string) uint64 {
+	return ChecksumString64(deriveSeedKey, namespace)
+}
+
+// Key128 is a 128-bit Meow checksum as two words, in the same form as
+// Checksum128. Unlike a byte slice it is comparable, so it can be used
+// directly as a map key.
+type Key128 struct {
+	Hi, Lo uint64
+}
+
+// Key returns the Meow checksum of data as a Key128.
+func Key(seed uint64, data []byte) Key128 {
+	sum := checksumOneShot(seed, data)
+	return Key128{
+		Hi: binary.LittleEndian.Uint64(sum[8:]),
+		Lo: binary.LittleEndian.Uint64(sum[:8]),
+	}
+}
+
+// Bytes returns the checksum as bytes, as returned by Checksum.
+func (k Key128) Bytes() [Size]byte {
+	var b [Size]byte
+	binary.LittleEndian.PutUint64(b[:8], k.Lo)
+	binary.LittleEndian.PutUint64(b[8:], k.Hi)
+	return b
+}
+
+// String returns the lowercase hex encoding of Bytes.
+func (k Key128) String() string {
+	b := k.Bytes()
+	return hex.EncodeToString(b[:])
+}
+
+// Bucket returns a bucket in [0, n) for key, derived from its 64-bit checksum.
+// It uses a multiply-shift reduction, which is faster than Checksum64 % n and
+// takes the bucket from the high bits of the product rather than the low bits
+// of the checksum. It panics if n is not positive.
+func Bucket(seed uint64, key []byte, n int) int {
+	if n <= 0 {
+		panic("meow: Bucket count must be positive")
+	}
+	hi, _ := bits.Mul64(Checksum64(seed, key), uint64(n))
+	return int(hi)
+}
@@ -0,0 +1,136 @@
+package meow
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+func TestChecksumUint64(t *testing.T) {
+	for trial := 0; trial < Trials(); trial++ {
+		seed, x := rand.Uint64(), rand.Uint64()
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], x)
+		if got, expect := ChecksumUint64(seed, x), Checksum64(seed, b[:]); got != expect {
+			t.Fatalf("ChecksumUint64(%#x, %#x) got=%016x expect=%016x", seed, x, got, expect)
+		}
+	}
+}
+
+func TestChecksumBytes16(t *testing.T) {
+	for trial := 0; trial < Trials(); trial++ {
+		seed := rand.Uint64()
+		var key [16]byte
+		rand.Read(key[:])
+		if got, expect := ChecksumBytes16(seed, key), Checksum64(seed, key[:]); got != expect {
+			t.Fatalf("ChecksumBytes16(%#x, %x) got=%016x expect=%016x", seed, key, got, expect)
+		}
+	}
+}
+
+func TestFixedSizeKeysAllocs(t *testing.T) {
+	var key [16]byte
+	allocs := testing.AllocsPerRun(100, func() {
+		ChecksumUint64(1, 2)
+		ChecksumBytes16(1, key)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs=%v expect=0", allocs)
+	}
+}
+
+func TestDeriveSeed(t *testing.T) {
+	// These values must never change.
+	cases := []struct {
+		Namespace string
+		Seed      uint64
+	}{
+		{"", 0x39b63a3f8034f0f3},
+		{"cache-v3", 0xd3b9441954f59bd8},
+		{"sessions", 0x332dffeac8f545ff},
+		{"meow", 0x07d1f65278d5e371},
+	}
+	for _, c := range cases {
+		if got := DeriveSeed(c.Namespace); got != c.Seed {
+			t.Errorf("DeriveSeed(%q) got=%#016x expect=%#016x", c.Namespace, got, c.Seed)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	data := []byte("Hello, World!")
+	k := Key(0, data)
+	hi, lo := Checksum128(0, data)
+	if k.Hi != hi || k.Lo != lo {
+		t.Fatalf("got=%016x:%016x expect=%016x:%016x", k.Hi, k.Lo, hi, lo)
+	}
+	if b, expect := k.Bytes(), Checksum(0, data); b != expect {
+		t.Fatalf("Bytes()=%x expect=%x", b, expect)
+	}
+	if k.String() != "a8cfb4aad7eada8ef007aafe27135386" {
+		t.Fatalf("String()=%s", k)
+	}
+}
+
+func TestKeyMap(t *testing.T) {
+	inputs := []string{"", "a", "b", "ab", "ba", "abc"}
+	m := map[Key128]int{}
+	for i, s := range inputs {
+		m[Key(0, []byte(s))] = i
+	}
+	if len(m) != len(inputs) {
+		t.Fatalf("%d distinct keys for %d inputs", len(m), len(inputs))
+	}
+	for i, s := range inputs {
+		if got := m[Key(0, []byte(s))]; got != i {
+			t.Errorf("m[Key(%q)]=%d expect=%d", s, got, i)
+		}
+	}
+	if Key(0, []byte("a")) == Key(1, []byte("a")) {
+		t.Error("different seeds produced the same key")
+	}
+}
+
+func TestBucket(t *testing.T) {
+	const n, keys = 16, 100000
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, n)
+	key := make([]byte, 12)
+	for i := 0; i < keys; i++ {
+		r.Read(key)
+		b := Bucket(3, key, n)
+		if b < 0 || b >= n {
+			t.Fatalf("Bucket()=%d out of range", b)
+		}
+		if again := Bucket(3, key, n); again != b {
+			t.Fatalf("Bucket() not deterministic: %d then %d", b, again)
+		}
+		counts[b]++
+	}
+
+	// Chi-square statistic with 15 degrees of freedom. The critical value at
+	// p = 0.001 is 37.7, so this fails only for a badly skewed distribution.
+	expect := float64(keys) / n
+	var chi2 float64
+	for _, c := range counts {
+		d := float64(c) - expect
+		chi2 += d * d / expect
+	}
+	if chi2 > 37.7 {
+		t.Errorf("chi-square=%.1f counts=%v", chi2, counts)
+	}
+
+	if b := Bucket(3, key, 1); b != 0 {
+		t.Errorf("Bucket(n=1)=%d", b)
+	}
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Bucket(n=%d) expected panic", n)
+				}
+			}()
+			Bucket(3, key, n)
+		}()
+	}
+}
@@ -146,7 +146,8 @@ func (a AESNI) Store(i int, m Array) {
 	a.g.inst("MOVOU", "X%d, %s", i, m.Addr(0))
 }
 
-// VAES256 implements block encryption with VAES-256.
+// VAES256 implements block encryption with VAES-256. It only uses the VEX
+// encoded forms on Y8-Y15, so it requires VAES and AVX but not AVX-512.
 type VAES256 struct {
 	g Generator
 }
@@ -159,37 +160,45 @@ func (v VAES256) Width() int { return 256 }
 
 func (v VAES256) Zero() {
 	for s := 0; s < 16; s += 2 {
-		i := 16 + (s / 2)
-		v.g.inst("VPXORQ", "Y%d, Y%d, Y%d", i, i, i)
+		i := 8 + (s / 2)
+		v.g.inst("VPXOR", "Y%d, Y%d, Y%d", i, i, i)
 	}
 }
 
 func (v VAES256) LoadStreams(m Array) {
 	for s := 0; s < 16; s += 2 {
-		i := 16 + (s / 2)
-		v.g.inst("VMOVDQU32", "%s, Y%d", m.Addr(s*aes.BlockSize), i)
+		i := 8 + (s / 2)
+		v.g.inst("VMOVDQU", "%s, Y%d", m.Addr(s*aes.BlockSize), i)
 	}
 }
 
+// StoreStreams also clears the upper halves of the YMM registers, to avoid the
+// AVX to SSE transition penalty in any code that follows.
 func (v VAES256) StoreStreams(m Array) {
 	for s := 0; s < 16; s += 2 {
-		i := 16 + (s / 2)
-		v.g.inst("VMOVDQU32", "Y%d, %s", i, m.Addr(s*aes.BlockSize))
+		i := 8 + (s / 2)
+		v.g.inst("VMOVDQU", "Y%d, %s", i, m.Addr(s*aes.BlockSize))
 	}
+	v.g.inst("VZEROUPPER", "")
 }
 
 func (v VAES256) AESBlock(m Array) {
 	for s := 0; s < 16; s += 2 {
-		i := 16 + (s / 2)
+		i := 8 + (s / 2)
 		v.g.inst("VAESDEC", "%s, Y%d, Y%d", m.Addr(s*aes.BlockSize), i, i)
 	}
 }
 
+// XMM extracts stream s into X<s>. Writing X<8+k> clobbers Y<8+k>, which holds
+// streams 2k and 2k+1. Those are extracted before it, except that stream 15 is
+// extracted from Y15 by the instruction that overwrites it. The upper halves
+// are cleared afterwards, since the finalization uses SSE instructions.
 func (v VAES256) XMM() {
 	for s := 0; s < 16; s++ {
-		i := 16 + (s / 2)
-		v.g.inst("VEXTRACTI32X4", "$%d, Y%d, X%d", s%2, i, s)
+		i := 8 + (s / 2)
+		v.g.inst("VEXTRACTI128", "$%d, Y%d, X%d", s%2, i, s)
 	}
+	v.g.inst("VZEROUPPER", "")
 }
 
 // VAES512 implements block encryption with VAES-512.
@@ -279,12 +288,12 @@ func (m *Meow) checksum(e BlockEncryptor) {
 	partial := f.Alloc(aes.BlockSize)
 
 	name := fmt.Sprintf("checksum%d", e.Width())
-	m.text(name, f.Size, 56)
+	m.text(name, f.Size, 48)
 
-	m.arg("seed", 0, "R8")
-	m.arg("dst_ptr", 8, "DI")
-	m.arg("src_ptr", 32, "SI")
-	m.arg("src_len", 40, "AX")
+	m.arg("seed", "seed", 0, "R8")
+	m.arg("src_ptr", "src_base", 8, "SI")
+	m.arg("src_len", "src_len", 16, "AX")
+	m.result("dst_ptr", "ret", 32, "DI")
 
 	m.section("Backup total input length.")
 	m.alloc("TOTAL_LEN", "R9")
@@ -322,9 +331,9 @@ func (m *Meow) blocks(e BlockEncryptor) {
 	name := fmt.Sprintf("blocks%d", e.Width())
 	m.text(name, f.Size, 48)
 
-	m.arg("s_ptr", 0, "DI")
-	m.arg("src_ptr", 24, "SI")
-	m.arg("src_len", 32, "AX")
+	m.arg("s_ptr", "s_base", 0, "DI")
+	m.arg("src_ptr", "src_base", 24, "SI")
+	m.arg("src_len", "src_len", 32, "AX")
 
 	streams := Array{Base: "S_PTR"}
 	src := Array{Base: "SRC_PTR"}
@@ -349,20 +358,20 @@ func (m *Meow) blocks(e BlockEncryptor) {
 
 // finish outputs a function to finish the Meow hash (partial blocks and mixing).
 func (m *Meow) finish() {
-	// func finishgo(seed uint64, s, dst, rem, trail []byte, length uint64) {
+	// func finishgo(seed uint64, s, rem, trail []byte, length uint64) [Size]byte
 	f := &StackFrame{}
 	mixer := f.Alloc(aes.BlockSize)
 	partial := f.Alloc(aes.BlockSize)
 
-	m.text("finish128", f.Size, 8+4*24+8)
+	m.text("finish128", f.Size, 8+3*24+8+16)
 
-	m.arg("seed", 0, "R8")
-	m.arg("s_ptr", 8, "R9")
-	m.arg("dst_ptr", 32, "DI")
-	m.arg("src_ptr", 56, "SI")
-	m.arg("src_len", 64, "AX")
-	m.arg("trail_ptr", 80, "R10")
-	m.arg("total_len", 104, "BX")
+	m.arg("seed", "seed", 0, "R8")
+	m.arg("s_ptr", "s_base", 8, "R9")
+	m.arg("src_ptr", "rem_base", 32, "SI")
+	m.arg("src_len", "rem_len", 40, "AX")
+	m.arg("trail_ptr", "trail_base", 56, "R10")
+	m.arg("total_len", "length", 80, "BX")
+	m.result("dst_ptr", "ret", 88, "DI")
 
 	b := NewAESNI(m)
 	b.LoadStreams(Array{Base: "S_PTR"})
@@ -496,14 +505,25 @@ func (m *Meow) ret() {
 	m.undefall()
 }
 
-// arg reads an argument, and allocates a register for it.
-func (m *Meow) arg(name string, offset int, reg string) {
+// arg reads the Go parameter param at offset, and allocates a register for it
+// under the given name.
+func (m *Meow) arg(name, param string, offset int, reg string) {
 	macro := m.alloc(name, reg)
-	m.inst("MOVQ", "%s+%d(FP), %s", name, offset, macro)
+	m.inst("MOVQ", "%s+%d(FP), %s", param, offset, macro)
+}
+
+// result allocates a register pointing to the result at offset.
+func (m *Meow) result(name, param string, offset int, reg string) {
+	macro := m.alloc(name, reg)
+	m.inst("LEAQ", "%s+%d(FP), %s", param, offset, macro)
 }
 
 // inst writes an instruction.
 func (m *Meow) inst(name, format string, args ...interface{}) {
+	if format == "" {
+		m.printf("\t%s\n", name)
+		return
+	}
 	args = append([]interface{}{name}, args...)
 	m.printf("\t%-8s "+format+"\n", args...)
 }
@@ -0,0 +1,88 @@
+package meow
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Serialized hash state layout.
+const (
+	magic         = "meow"
+	marshaledSize = len(magic) + 1 + 8 + BlockSize + BlockSize + 8 + aes.BlockSize + 8 + 8
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoded state is
+// tagged with the Meow version, and can be resumed with UnmarshalBinary.
+func (d *Digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, magic...)
+	b = append(b, Version)
+	b = appendUint64(b, d.seed)
+	b = append(b, d.s[:]...)
+	b = append(b, d.b[:]...)
+	b = appendUint64(b, uint64(d.n))
+	b = append(b, d.t[:]...)
+	b = appendUint64(b, d.length)
+	b = appendUint64(b, uint64(d.Size()))
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It restores a hash
+// state produced by MarshalBinary. The state is validated, so corrupt or
+// truncated input returns an error and leaves d unchanged.
+func (d *Digest) UnmarshalBinary(b []byte) error {
+	if len(b) < len(magic)+1 || string(b[:len(magic)]) != magic {
+		return errors.New("meow: invalid hash state identifier")
+	}
+	b = b[len(magic):]
+	if v := int(b[0]); v != Version {
+		return fmt.Errorf("meow: hash state from version %d, expected %d", v, Version)
+	}
+	b = b[1:]
+	if len(b) != marshaledSize-len(magic)-1 {
+		return errors.New("meow: invalid hash state size")
+	}
+
+	var r Digest
+	b, r.seed = consumeUint64(b)
+	b = b[copy(r.s[:], b):]
+	b = b[copy(r.b[:], b):]
+	b, n := consumeUint64(b)
+	b = b[copy(r.t[:], b):]
+	b, r.length = consumeUint64(b)
+	_, size := consumeUint64(b)
+
+	// Pending data is always the tail of the input after the last full block.
+	if n >= BlockSize || n != r.length%BlockSize {
+		return fmt.Errorf("meow: invalid pending length %d in hash state of length %d", n, r.length)
+	}
+	if size < 1 || size > Size {
+		return fmt.Errorf("meow: invalid digest size %d in hash state", size)
+	}
+	r.n = int(n)
+	r.size = int(size)
+	*d = r
+	return nil
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	var a [8]byte
+	binary.BigEndian.PutUint64(a[:], x)
+	return append(b, a[:]...)
+}
+
+func consumeUint64(b []byte) ([]byte, uint64) {
+	return b[8:], binary.BigEndian.Uint64(b)
+}
+
+// GobEncode implements gob.GobEncoder, using the binary encoding of the state.
+func (d *Digest) GobEncode() ([]byte, error) {
+	return d.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder.
+func (d *Digest) GobDecode(b []byte) error {
+	return d.UnmarshalBinary(b)
+}
@@ -0,0 +1,119 @@
+package meow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"testing"
+)
+
+func TestHashMarshalBinary(t *testing.T) {
+	CheckEqual(t, checksumHash, checksumHashWithMarshal)
+}
+
+func TestMarshalBinaryPreservesSize(t *testing.T) {
+	h := New32(42)
+	h.Write([]byte("resumed digests keep their size"))
+	state, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var r Digest
+	if err := r.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(h.Sum(nil), r.Sum(nil)) {
+		t.Fatalf("got=%x expect=%x", r.Sum(nil), h.Sum(nil))
+	}
+}
+
+func TestUnmarshalBinaryVersionMismatch(t *testing.T) {
+	state, err := New(0).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state[len(magic)] = Version - 1
+
+	var d Digest
+	if err := d.UnmarshalBinary(state); err == nil {
+		t.Fatal("expected error for state from another version")
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	h := New64(3)
+	h.Write(make([]byte, BlockSize+10))
+	state, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Offsets of the n and size fields.
+	nOffset := len(magic) + 1 + 8 + 2*BlockSize
+	sizeOffset := marshaledSize - 8
+	tamper := func(offset int, value uint64) []byte {
+		b := append([]byte(nil), state...)
+		binary.BigEndian.PutUint64(b[offset:], value)
+		return b
+	}
+
+	cases := map[string][]byte{
+		"empty":           nil,
+		"magic only":      []byte(magic),
+		"bad magic":       append([]byte("woof"), state[len(magic):]...),
+		"unknown version": append(append([]byte(magic), Version+1), state[len(magic)+1:]...),
+		"truncated":       state[:len(state)-1],
+		"extended":        append(append([]byte(nil), state...), 0),
+		"n too large":     tamper(nOffset, BlockSize),
+		"n huge":          tamper(nOffset, 1<<63),
+		"n mismatch":      tamper(nOffset, 11),
+		"size zero":       tamper(sizeOffset, 0),
+		"size too large":  tamper(sizeOffset, Size+1),
+	}
+	for name, b := range cases {
+		d := New(9)
+		before := *d
+		if err := d.UnmarshalBinary(b); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if *d != before {
+			t.Errorf("%s: Digest modified by failed UnmarshalBinary", name)
+		}
+	}
+
+	var r Digest
+	if err := r.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+	if r.Sum64() != h.Sum64() || r.Size() != h.Size() {
+		t.Fatalf("round trip got=%x expect=%x", r.Sum(nil), h.Sum(nil))
+	}
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	type checkpoint struct {
+		Name   string
+		Digest *Digest
+	}
+
+	b := []byte("a digest embedded in a gob-encoded struct resumes mid-stream")
+	half := len(b) / 2
+	h := New64(7)
+	h.Write(b[:half])
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(checkpoint{Name: "ingest", Digest: h}); err != nil {
+		t.Fatal(err)
+	}
+	var c checkpoint
+	if err := gob.NewDecoder(&buf).Decode(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Write(b[half:])
+	c.Digest.Write(b[half:])
+	if !bytes.Equal(h.Sum(nil), c.Digest.Sum(nil)) {
+		t.Fatalf("got=%x expect=%x", c.Digest.Sum(nil), h.Sum(nil))
+	}
+}
@@ -3,6 +3,12 @@ package meow
 import (
 	"crypto/aes"
 	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"net"
 )
 
 //go:generate go run make_block.go
@@ -20,32 +26,221 @@ const BlockSize = 256
 const Size = 16
 
 // Variables capturing the implementation. Default to the pure go fallback.
+//
+// They are set during package initialization, before any goroutine can hash,
+// and are only read after that, so hashing from any number of goroutines is
+// free of data races without synchronizing each call. ForcePureGo and
+// UseAccelerated write them, and so must not run concurrently with hashing.
 var (
 	implementation = "go"
 	checksum       = checksumgo
 	blocks         = blocksgo
 	finish         = finishgo
+
+	// finishAsm reports whether finish is finish128. finishDirect relies on it
+	// to call the same function as finish without using the variable, so the
+	// two must always be set together.
+	finishAsm = false
 )
 
-// Checksum returns the Meow checksum of data.
+// ForcePureGo switches to the pure Go implementation, regardless of the
+// features available on this CPU. It is intended for testing and debugging,
+// and must not be called concurrently with any hashing.
+func ForcePureGo() {
+	implementation = "go"
+	checksum = checksumgo
+	blocks = blocksgo
+	finish = finishgo
+	finishAsm = false
+}
+
+// UseAccelerated selects the fastest implementation supported by this CPU,
+// undoing a previous call to ForcePureGo. It reports whether an accelerated
+// implementation is in use. Like ForcePureGo, it must not be called
+// concurrently with any hashing.
+func UseAccelerated() bool {
+	ForcePureGo()
+	return accelerate()
+}
+
+// Implementation returns the name of the Meow implementation selected for
+// this CPU, for example "go", "aes-ni" or "vaes-512".
+func Implementation() string {
+	return implementation
+}
+
+// Blocks absorbs block into streams using the selected implementation. It is
+// the low-level primitive underneath Digest, exposed for advanced users
+// building their own streaming layer, and most code should use Digest
+// instead. streams must be BlockSize long and all zero at the start of a
+// message, and block a multiple of BlockSize long, otherwise Blocks panics.
+// The checksum also depends on the trailing bytes and total length of the
+// message, which Blocks does not track.
+func Blocks(streams, block []byte) {
+	if len(streams) != BlockSize {
+		panic("meow: Blocks streams must have length BlockSize")
+	}
+	if len(block)%BlockSize != 0 {
+		panic("meow: Blocks block length must be a multiple of BlockSize")
+	}
+	if len(block) > 0 {
+		blocks(streams, block)
+	}
+}
+
+// Checksum returns the Meow checksum of data. A nil and an empty data have the
+// same checksum, which depends only on the seed.
 func Checksum(seed uint64, data []byte) [Size]byte {
-	var dst [Size]byte
-	checksum(seed, dst[:], data)
+	return checksumOneShot(seed, data)
+}
+
+// ChecksumString returns the Meow checksum of s, without converting it to a
+// byte slice.
+func ChecksumString(seed uint64, s string) [Size]byte {
+	return checksumOneShot(seed, stringBytes(s))
+}
+
+// checksumOneShot returns the checksum of data. Every one-shot entry point
+// calls it rather than checksum, so that data shorter than BlockSize skips the
+// block loop.
+func checksumOneShot(seed uint64, data []byte) [Size]byte {
+	if len(data) < BlockSize {
+		return checksumShort(seed, data)
+	}
+	return checksum(seed, data)
+}
+
+// checksumShort returns the checksum of data shorter than BlockSize. Such data
+// has no full blocks, so the streams stay zero and only the finalization needs
+// to run.
+func checksumShort(seed uint64, data []byte) [Size]byte {
+	trail := data
+	if len(data) >= aes.BlockSize {
+		trail = data[len(data)-aes.BlockSize:]
+	}
+	return finishDirect(seed, zeroStreams[:], data, trail, uint64(len(data)))
+}
+
+// ChecksumStrings returns the Meow checksum of the concatenation of parts,
+// without joining them. Like any concatenation it is ambiguous: "a", "bc" and
+// "ab", "c" have the same checksum. Use a separator that cannot occur in the
+// parts, or ChecksumStringsPrefixed, when that matters.
+func ChecksumStrings(seed uint64, parts ...string) [Size]byte {
+	d := Get(seed)
+	defer Put(d)
+	for _, p := range parts {
+		d.WriteString(p)
+	}
+	return d.sum()
+}
+
+// ChecksumStringsPrefixed returns the Meow checksum of parts, each preceded by
+// its length as a little-endian uint64. Different groupings of the same bytes
+// therefore have different checksums.
+func ChecksumStringsPrefixed(seed uint64, parts ...string) [Size]byte {
+	d := Get(seed)
+	defer Put(d)
+	for _, p := range parts {
+		n := uint64(len(p))
+		for i := 0; i < 8; i++ {
+			d.WriteByte(byte(n >> (8 * i)))
+		}
+		d.WriteString(p)
+	}
+	return d.sum()
+}
+
+// ChecksumString64 returns the 64-bit checksum of s.
+func ChecksumString64(seed uint64, s string) uint64 {
+	c := checksumOneShot(seed, stringBytes(s))
+	return binary.LittleEndian.Uint64(c[:8])
+}
+
+// ChecksumTo writes the Meow checksum of data to dst, which must be a slice of
+// length 16. It is the zero allocation version of Checksum.
+func ChecksumTo(seed uint64, dst, data []byte) {
+	if len(dst) != Size {
+		panic("meow: ChecksumTo destination must have length 16")
+	}
+	c := checksumOneShot(seed, data)
+	copy(dst, c[:])
+}
+
+// AppendChecksum appends the Meow checksum of data to dst and returns the
+// extended slice. It does not allocate if dst has sufficient capacity.
+func AppendChecksum(dst []byte, seed uint64, data []byte) []byte {
+	sum := checksumOneShot(seed, data)
+	return append(dst, sum[:]...)
+}
+
+// AppendHex appends the lowercase hex encoding of the Meow checksum of data to
+// dst and returns the extended slice. It does not allocate if dst has
+// sufficient capacity.
+func AppendHex(dst []byte, seed uint64, data []byte) []byte {
+	return appendHex(dst, seed, data, "0123456789abcdef")
+}
+
+// AppendHexUpper is like AppendHex, but uses uppercase hex digits.
+func AppendHexUpper(dst []byte, seed uint64, data []byte) []byte {
+	return appendHex(dst, seed, data, "0123456789ABCDEF")
+}
+
+// appendHex appends the checksum and then encodes it in place, working back
+// from the end so no byte is overwritten before it is encoded.
+func appendHex(dst []byte, seed uint64, data []byte, digits string) []byte {
+	n := len(dst)
+	dst = AppendChecksum(dst, seed, data)
+	dst = append(dst, make([]byte, Size)...)
+	for i := Size - 1; i >= 0; i-- {
+		b := dst[n+i]
+		dst[n+2*i] = digits[b>>4]
+		dst[n+2*i+1] = digits[b&0x0f]
+	}
 	return dst
 }
 
-// Checksum64 returns the 64-bit checksum of data.
+// ChecksumBuffers returns the Meow checksum of the concatenation of bufs,
+// without joining them.
+func ChecksumBuffers(seed uint64, bufs net.Buffers) [Size]byte {
+	d := Get(seed)
+	defer Put(d)
+	for _, b := range bufs {
+		d.Write(b)
+	}
+	return d.sum()
+}
+
+// ConcatChecksum returns the Meow checksum of the concatenation of parts,
+// hashing each part in turn without joining them. The states of two running
+// Digests cannot be combined, so data hashed in pieces must be fed through a
+// single Digest like this.
+func ConcatChecksum(seed uint64, parts ...[]byte) [Size]byte {
+	return ChecksumBuffers(seed, parts)
+}
+
+// Checksum64 returns the 64-bit checksum of data: the first 8 bytes of the
+// 128-bit checksum as a little-endian integer, the same lane the reference
+// implementation takes.
 func Checksum64(seed uint64, data []byte) uint64 {
 	c := Checksum(seed, data)
 	return binary.LittleEndian.Uint64(c[:8])
 }
 
-// Checksum32 returns the 32-bit checksum of data.
+// Checksum32 returns the 32-bit checksum of data: the first 4 bytes of the
+// 128-bit checksum as a little-endian integer. Like the reference
+// implementation, it truncates the checksum rather than folding it.
 func Checksum32(seed uint64, data []byte) uint32 {
 	c := Checksum(seed, data)
 	return binary.LittleEndian.Uint32(c[:4])
 }
 
+// Checksum128 returns the checksum of data as two little-endian words. lo is
+// the first 8 bytes of the checksum, and equal to Checksum64.
+func Checksum128(seed uint64, data []byte) (hi, lo uint64) {
+	c := checksumOneShot(seed, data)
+	return binary.LittleEndian.Uint64(c[8:]), binary.LittleEndian.Uint64(c[:8])
+}
+
 // New returns a 128-bit Meow hash.
 func New(seed uint64) *Digest {
 	return new(seed, Size)
@@ -61,23 +256,69 @@ func New32(seed uint64) *Digest {
 	return new(seed, 4)
 }
 
+// NewWithSize returns a Meow hash truncated to size bytes. The truncated hash
+// is a prefix of the 128-bit hash. It panics unless 1 <= size <= 16.
+func NewWithSize(seed uint64, size int) *Digest {
+	return new(seed, size)
+}
+
+// NewFunc returns a function creating 128-bit Meow hashes with the given seed,
+// for use with APIs that take a hash.Hash constructor.
+func NewFunc(seed uint64) func() hash.Hash {
+	return func() hash.Hash { return New(seed) }
+}
+
+// Hash returns a 128-bit Meow hash with seed 0 as a hash.Hash. It may be
+// passed directly to APIs that take a hash.Hash constructor.
+func Hash() hash.Hash {
+	return New(0)
+}
+
+// ByName returns a Meow hash with the given seed by name, for selecting a
+// hash from configuration. The names are "meow" for the 128-bit hash, and
+// "meow64" and "meow32" for the hashes returned by New64 and New32.
+func ByName(name string, seed uint64) (hash.Hash, error) {
+	switch name {
+	case "meow":
+		return New(seed), nil
+	case "meow64":
+		return New64(seed), nil
+	case "meow32":
+		return New32(seed), nil
+	default:
+		return nil, fmt.Errorf("meow: unknown hash name %q", name)
+	}
+}
+
+// new returns a Digest of the given size. Every constructor goes through new,
+// so it panics on a size Sum could not honor.
 func new(seed uint64, size int) *Digest {
+	if size < 1 || size > Size {
+		panic("meow: invalid digest size")
+	}
 	return &Digest{seed: seed, size: size}
 }
 
-// Digest computes Meow hash in a streaming fashion.
+// Digest computes Meow hash in a streaming fashion. It implements hash.Hash,
+// hash.Hash32 and hash.Hash64. The zero value is a 128-bit hash with seed 0,
+// equivalent to New(0).
 type Digest struct {
-	seed   uint64          // hash seed
-	s      [BlockSize]byte // streams
-	b      [BlockSize]byte // pending block
-	n      int             // number of (initial) bytes populated in b
-	t      []byte          // the trailing block of data written to the hash
-	length uint64          // total length written
-	size   int             // hash size in bytes
+	seed   uint64              // hash seed
+	s      [BlockSize]byte     // streams
+	b      [BlockSize]byte     // pending block
+	n      int                 // number of (initial) bytes populated in b
+	t      [aes.BlockSize]byte // the trailing block of data written to the hash
+	length uint64              // total length written
+	size   int                 // hash size in bytes, or 0 for Size
 }
 
 // Size returns the number of bytes Sum will return.
-func (d *Digest) Size() int { return d.size }
+func (d *Digest) Size() int {
+	if d.size == 0 {
+		return Size
+	}
+	return d.size
+}
 
 // BlockSize returns the hash's underlying block size.
 // The Write method must be able to accept any amount
@@ -85,30 +326,77 @@ func (d *Digest) Size() int { return d.size }
 // are a multiple of the block size.
 func (d *Digest) BlockSize() int { return BlockSize }
 
-// Reset resets the Hash to its initial state.
+// Version returns the Meow hash version computed by the Digest. This package
+// implements a single version, so it is always Version.
+func (d *Digest) Version() int { return Version }
+
+// VersionName returns the name of the Meow hash version computed by the
+// Digest, always VersionName.
+func (d *Digest) VersionName() string { return VersionName }
+
+// Seed returns the hash seed.
+func (d *Digest) Seed() uint64 { return d.seed }
+
+// BytesWritten returns the number of bytes written since the Digest was
+// created or Reset.
+func (d *Digest) BytesWritten() uint64 { return d.length }
+
+// Reset resets the Hash to its initial state. Pending data is cleared, so no
+// previously written bytes remain in the Digest.
 func (d *Digest) Reset() {
 	for i := 0; i < BlockSize; i++ {
 		d.s[i] = 0
+		d.b[i] = 0
 	}
 	d.n = 0
 	d.length = 0
-	d.t = nil
+	d.t = [aes.BlockSize]byte{}
+}
+
+// Zeroize overwrites the entire state of the Digest, including the seed and
+// size, so that no data written to it remains in memory. Unlike Reset, the
+// configuration is lost: the Digest is left as the zero value, which is
+// equivalent to New(0). In particular a zeroized New64 or New32 Digest
+// silently becomes a 128-bit hash with seed 0.
+func (d *Digest) Zeroize() {
+	*d = Digest{}
+}
+
+// Clone returns a copy of the Digest. Further writes to either do not affect
+// the other.
+func (d *Digest) Clone() *Digest {
+	c := *d
+	return &c
 }
 
 // Write (via the embedded io.Writer interface) adds more data to the running hash.
 // It never returns an error.
+//
+// The total length is folded into the checksum, so Write panics rather than
+// let it wrap if more than 2^64-1 bytes would be written since the Digest was
+// created or Reset. The Digest is unchanged in that case.
 func (d *Digest) Write(p []byte) (int, error) {
 	N := len(p)
+	if d.length+uint64(N) < d.length {
+		panic("meow: total length written overflows 64 bits")
+	}
 	d.length += uint64(N)
 
-	// Update trailing block.
-	if len(p) >= aes.BlockSize {
-		d.t = p[N-aes.BlockSize:]
+	// Update trailing block. Bytes are copied so that p is not retained.
+	if N >= aes.BlockSize {
+		copy(d.t[:], p[N-aes.BlockSize:])
 	} else {
-		d.t = append(d.t, p...)
+		copy(d.t[:], d.t[N:])
+		copy(d.t[aes.BlockSize-N:], p)
 	}
-	if len(d.t) > aes.BlockSize {
-		d.t = d.t[len(d.t)-aes.BlockSize:]
+
+	// Block aligned writes with nothing pending go straight to the streams,
+	// without touching the pending block.
+	if d.n == 0 && N&(BlockSize-1) == 0 {
+		if N > 0 {
+			blocks(d.s[:], p)
+		}
+		return N, nil
 	}
 
 	// Combine with any pending data.
@@ -137,27 +425,134 @@ func (d *Digest) Write(p []byte) (int, error) {
 	return N, nil
 }
 
+// WriteByte implements io.ByteWriter, adding c to the running hash. It never
+// returns an error.
+func (d *Digest) WriteByte(c byte) error {
+	if d.length == math.MaxUint64 {
+		panic("meow: total length written overflows 64 bits")
+	}
+	d.length++
+	copy(d.t[:], d.t[1:])
+	d.t[aes.BlockSize-1] = c
+	d.b[d.n] = c
+	d.n++
+	if d.n == BlockSize {
+		blocks(d.s[:], d.b[:])
+		d.n = 0
+	}
+	return nil
+}
+
+// WriteString adds the bytes of s to the running hash, without converting it to
+// a byte slice. It never returns an error.
+func (d *Digest) WriteString(s string) (int, error) {
+	return d.Write(stringBytes(s))
+}
+
+// ReadFrom implements io.ReaderFrom, adding all data read from r to the running
+// hash until EOF. It returns the number of bytes read, and any error other
+// than io.EOF.
+func (d *Digest) ReadFrom(r io.Reader) (int64, error) {
+	p := getReadBuffer()
+	defer readBuffers.Put(p)
+	return d.readFrom(r, p[:], nil)
+}
+
 // Sum appends the current hash to b and returns the resulting slice.
-// It does not change the underlying hash state.
+// It does not change the underlying hash state. The hash is finished from
+// a copy of the state on the stack, so Sum allocates only if b lacks the
+// capacity for Size() more bytes; h.Sum(buf[:0]) with a large enough buf does
+// not allocate.
 func (d *Digest) Sum(b []byte) []byte {
-	var dst [Size]byte
-	dt := *d
-	finish(dt.seed, dt.s[:], dst[:], dt.b[:d.n], dt.t, dt.length)
-	return append(b, dst[:dt.size]...)
+	dst := d.sum()
+	return append(b, dst[:d.Size()]...)
 }
 
-// SumTo copies the current hash to dst. It is essentially the zero
-// allocation version of Sum. dst must be a slice of length 16.
+// SumTo copies the current hash to dst, writing Size() bytes. It is
+// essentially the zero allocation version of Sum. It panics if dst is shorter
+// than Size(). It does not change the underlying hash state.
 func (d *Digest) SumTo(dst []byte) {
-	finish(d.seed, d.s[:], dst, d.b[:d.n], d.t, d.length)
+	size := d.Size()
+	if len(dst) < size {
+		panic("meow: SumTo destination is shorter than the digest size")
+	}
+	sum := d.sum()
+	copy(dst, sum[:size])
+}
+
+// SumToN copies the first n bytes of the full 128-bit hash to dst, regardless
+// of the digest size. It panics if n is not between 1 and Size, or dst is
+// shorter than n. It does not change the underlying hash state.
+func (d *Digest) SumToN(dst []byte, n int) {
+	if n < 1 || n > Size {
+		panic("meow: SumToN length must be between 1 and 16")
+	}
+	if len(dst) < n {
+		panic("meow: SumToN destination is shorter than n")
+	}
+	sum := d.sum()
+	copy(dst, sum[:n])
+}
+
+// WriteTo implements io.WriterTo, writing the current hash, truncated to
+// Size() bytes, to w. It returns the number of bytes written and any error
+// from w, or io.ErrShortWrite if w wrote fewer bytes without an error. It does
+// not change the underlying hash state.
+func (d *Digest) WriteTo(w io.Writer) (int64, error) {
+	sum := d.sum()
+	n, err := w.Write(sum[:d.Size()])
+	if err == nil && n < d.Size() {
+		err = io.ErrShortWrite
+	}
+	return int64(n), err
 }
 
-// Sum32 implements hash.Hash32 interface
+// Sum32 implements hash.Hash32 interface. It returns the first 4 bytes of the
+// full 128-bit hash, regardless of the digest size.
 func (d *Digest) Sum32() uint32 {
-	return binary.LittleEndian.Uint32(d.Sum(nil))
+	dst := d.sum()
+	return binary.LittleEndian.Uint32(dst[:4])
 }
 
-// Sum64 implements hash.Hash64 interface
+// Sum64 implements hash.Hash64 interface. It returns the first 8 bytes of the
+// full 128-bit hash, regardless of the digest size.
 func (d *Digest) Sum64() uint64 {
-	return binary.LittleEndian.Uint64(d.Sum(nil))
+	dst := d.sum()
+	return binary.LittleEndian.Uint64(dst[:8])
+}
+
+// Sum128 returns the full 128-bit hash as two little-endian words, regardless
+// of the digest size. lo is the first 8 bytes of the hash, and equal to Sum64.
+func (d *Digest) Sum128() (hi, lo uint64) {
+	dst := d.sum()
+	return binary.LittleEndian.Uint64(dst[8:]), binary.LittleEndian.Uint64(dst[:8])
+}
+
+// Sum16 returns the full 128-bit hash as an array, regardless of the digest
+// size. It does not allocate or change the underlying hash state.
+func (d *Digest) Sum16() [Size]byte {
+	return d.sum()
+}
+
+// SumInto writes the full 128-bit hash to dst, regardless of the digest size.
+// It does not allocate or change the underlying hash state.
+func (d *Digest) SumInto(dst *[Size]byte) {
+	*dst = d.sum()
+}
+
+// String returns the lowercase hex encoding of Sum(nil). It does not change
+// the underlying hash state.
+func (d *Digest) String() string {
+	return hex.EncodeToString(d.Sum(nil))
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the current hash as
+// lowercase hex. It does not change the underlying hash state.
+func (d *Digest) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// sum returns the full 128-bit hash without changing the hash state.
+func (d *Digest) sum() [Size]byte {
+	return finish(d.seed, d.s[:], d.b[:d.n], d.t[:], d.length)
 }
@@ -0,0 +1,71 @@
+package meow_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pckhoi/meow"
+)
+
+// benchmarkSizes are representative input sizes, from short keys to large
+// buffers.
+var benchmarkSizes = []int{16, 256, 4 << 10, 64 << 10, 1 << 20}
+
+// benchmarkChunk is the size of each Write in the streaming benchmarks.
+const benchmarkChunk = 4 << 10
+
+// BenchmarkBackends measures Checksum and streaming Write throughput with both
+// the pure Go and the accelerated implementation, so a regression in either is
+// visible on any machine.
+func BenchmarkBackends(b *testing.B) {
+	defer meow.UseAccelerated()
+
+	impls := []struct {
+		Name   string
+		Select func() bool
+	}{
+		{"go", func() bool { meow.ForcePureGo(); return true }},
+		{"accelerated", meow.UseAccelerated},
+	}
+	for _, impl := range impls {
+		impl := impl
+		b.Run("impl="+impl.Name, func(b *testing.B) {
+			if !impl.Select() {
+				b.Skip("no accelerated implementation on this CPU")
+			}
+			b.Run("op=Checksum", func(b *testing.B) {
+				for _, size := range benchmarkSizes {
+					data := buffer[:size]
+					b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+						b.SetBytes(int64(size))
+						for i := 0; i < b.N; i++ {
+							sum := meow.Checksum(0, data)
+							sink += sum[0]
+						}
+					})
+				}
+			})
+			b.Run("op=Write", func(b *testing.B) {
+				h := meow.New(0)
+				for _, size := range benchmarkSizes {
+					data := buffer[:size]
+					b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+						b.SetBytes(int64(size))
+						for i := 0; i < b.N; i++ {
+							h.Reset()
+							for p := data; len(p) > 0; {
+								n := benchmarkChunk
+								if n > len(p) {
+									n = len(p)
+								}
+								h.Write(p[:n])
+								p = p[n:]
+							}
+							sink += byte(h.Sum64())
+						}
+					})
+				}
+			})
+		})
+	}
+}
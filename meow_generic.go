@@ -6,17 +6,16 @@ import (
 )
 
 // checksumgo is a pure go implementation of Meow checksum.
-func checksumgo(seed uint64, dst, src []byte) {
+func checksumgo(seed uint64, src []byte) [Size]byte {
 	var s [BlockSize]byte
 
 	if len(src) < aes.BlockSize {
-		finish(seed, s[:], dst, src, src, uint64(len(src)))
-		return
+		return finishgo(seed, s[:], src, src, uint64(len(src)))
 	}
 
 	n := len(src) &^ (BlockSize - 1)
-	blocks(s[:], src[:n])
-	finish(seed, s[:], dst, src[n:], src[len(src)-aes.BlockSize:], uint64(len(src)))
+	blocksgo(s[:], src[:n])
+	return finishgo(seed, s[:], src[n:], src[len(src)-aes.BlockSize:], uint64(len(src)))
 }
 
 // blocksgo hashes some number of full blocks into streams.
@@ -25,15 +24,61 @@ func blocksgo(s, src []byte) {
 		panic("blocks can only process multiples of BlockSize")
 	}
 
-	for len(src) >= BlockSize {
+	// Streams are independent, so each is processed in turn with its state held
+	// in local variables. Data is consumed in chunks so that it stays in cache
+	// across the passes for each stream.
+	for len(src) > 0 {
+		n := len(src)
+		if n > blocksChunkSize {
+			n = blocksChunkSize
+		}
 		for i := 0; i < BlockSize; i += aes.BlockSize {
-			aesdec(src[i:], s[i:], s[i:])
+			blocksStream(s[i:i+aes.BlockSize], src[i:n])
+		}
+		src = src[n:]
+	}
+}
+
+// blocksChunkSize is the amount of data processed at once by blocksgo.
+const blocksChunkSize = 16 * BlockSize
+
+// blocksStream hashes one stream with the 16-byte block at the start of src and
+// every BlockSize bytes thereafter. The loop is written so the compiler can
+// prove every slice in bounds, which -gcflags=-d=ssa/check_bce confirms.
+func blocksStream(s, src []byte) {
+	s = s[:aes.BlockSize]
+	s0 := binary.BigEndian.Uint32(s[0:4])
+	s1 := binary.BigEndian.Uint32(s[4:8])
+	s2 := binary.BigEndian.Uint32(s[8:12])
+	s3 := binary.BigEndian.Uint32(s[12:16])
+
+	for len(src) >= aes.BlockSize {
+		k := src[:aes.BlockSize]
+		t0 := binary.BigEndian.Uint32(k[0:4]) ^ td0[uint8(s0>>24)] ^ td1[uint8(s3>>16)] ^ td2[uint8(s2>>8)] ^ td3[uint8(s1)]
+		t1 := binary.BigEndian.Uint32(k[4:8]) ^ td0[uint8(s1>>24)] ^ td1[uint8(s0>>16)] ^ td2[uint8(s3>>8)] ^ td3[uint8(s2)]
+		t2 := binary.BigEndian.Uint32(k[8:12]) ^ td0[uint8(s2>>24)] ^ td1[uint8(s1>>16)] ^ td2[uint8(s0>>8)] ^ td3[uint8(s3)]
+		t3 := binary.BigEndian.Uint32(k[12:16]) ^ td0[uint8(s3>>24)] ^ td1[uint8(s2>>16)] ^ td2[uint8(s1>>8)] ^ td3[uint8(s0)]
+		s0, s1, s2, s3 = t0, t1, t2, t3
+
+		if len(src) < BlockSize {
+			break
 		}
 		src = src[BlockSize:]
 	}
+
+	binary.BigEndian.PutUint32(s[0:4], s0)
+	binary.BigEndian.PutUint32(s[4:8], s1)
+	binary.BigEndian.PutUint32(s[8:12], s2)
+	binary.BigEndian.PutUint32(s[12:16], s3)
 }
 
-func finishgo(seed uint64, s, dst, rem, trail []byte, length uint64) {
+// finishgo hashes the remaining data and returns the checksum. The streams s
+// are not modified.
+func finishgo(seed uint64, streams, rem, trail []byte, length uint64) [Size]byte {
+	var state [BlockSize]byte
+	s := state[:]
+	copy(s, streams)
+
 	// Handle 16-byte blocks.
 	i := 0
 	for len(rem) >= aes.BlockSize {
@@ -71,7 +116,9 @@ func finishgo(seed uint64, s, dst, rem, trail []byte, length uint64) {
 		aesdec(mixer[:], m0, m0)
 	}
 
-	copy(dst, m0)
+	var dst [Size]byte
+	copy(dst[:], m0)
+	return dst
 }
 
 // aesdec performs one round of AES decryption.
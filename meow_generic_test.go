@@ -0,0 +1,71 @@
+package meow
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+// checkBlocks confirms blocksgo matches blocksReference on the given streams and data.
+func checkBlocks(t *testing.T, s, data []byte) {
+	t.Helper()
+	data = data[:len(data)&^(BlockSize-1)]
+
+	expect := append([]byte(nil), s...)
+	blocksReference(expect, data)
+	got := append([]byte(nil), s...)
+	blocksgo(got, data)
+
+	if !bytes.Equal(got, expect) {
+		t.Fatalf("streams mismatch\n   got=%x\nexpect=%x", got, expect)
+	}
+}
+
+func TestBlocksGoMatchesReference(t *testing.T) {
+	for trial := 0; trial < Trials(); trial++ {
+		s := make([]byte, BlockSize)
+		data := make([]byte, rand.Intn(8<<10))
+		rand.Read(s)
+		rand.Read(data)
+		checkBlocks(t, s, data)
+	}
+}
+
+// TestFinishGoLongLengths pins finishgo for lengths beyond 32 bits, computed
+// by the assembly implementation, so 64-bit arithmetic is checked on 32-bit
+// architectures too.
+func TestFinishGoLongLengths(t *testing.T) {
+	var s [BlockSize]byte
+	for i := range s {
+		s[i] = byte(i)
+	}
+	var trail [aes.BlockSize]byte
+	for i := range trail {
+		trail[i] = byte(0xf0 + i)
+	}
+	cases := []struct {
+		Length uint64
+		Expect string
+	}{
+		{0x100000000, "8c27f12d6c4a225c010e36b980cdf7fc"},
+		{0x100000011, "ad6a4e4cb1766cf7096aecfbb871f371"},
+		{0xffffffffffffffff, "70535ff3e75b66dd066458bb4e1f1d69"},
+	}
+	for _, c := range cases {
+		got := finishgo(0x0123456789abcdef, s[:], nil, trail[:], c.Length)
+		if hex.EncodeToString(got[:]) != c.Expect {
+			t.Errorf("length %#x: got=%x expect=%s", c.Length, got, c.Expect)
+		}
+	}
+}
+
+func BenchmarkBlocksGo(b *testing.B) {
+	var s [BlockSize]byte
+	data := make([]byte, 1<<20)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		blocksgo(s[:], data)
+	}
+}
@@ -2,7 +2,19 @@ package meow
 
 import (
 	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 )
 
 func TestVectorsChecksum(t *testing.T) {
@@ -12,6 +24,46 @@ func TestVectorsChecksum(t *testing.T) {
 	}
 }
 
+// edgeLengths are the lengths either side of block boundaries that the
+// reference vectors must include. It must match edge[] in
+// testdata/testvectors.cc.
+var edgeLengths = []int{255, 256, 257, 511, 512, 513, 4095, 4096, 4097}
+
+// TestVectorsCoverage confirms the reference vectors include all small lengths,
+// the edge lengths, and every length modulo BlockSize.
+func TestVectorsCoverage(t *testing.T) {
+	testdata := LoadTestData(t)
+	lengths := map[int]bool{}
+	residues := map[int]bool{}
+	for _, v := range testdata.TestVectors {
+		lengths[len(v.Input)] = true
+		residues[len(v.Input)%BlockSize] = true
+	}
+	for n := 0; n < 2*aes.BlockSize; n++ {
+		if !lengths[n] {
+			t.Errorf("no test vector of length %d", n)
+		}
+	}
+	for _, n := range edgeLengths {
+		if !lengths[n] {
+			t.Errorf("no test vector of edge length %d", n)
+		}
+	}
+	if len(residues) != BlockSize {
+		t.Errorf("test vectors cover %d lengths modulo %d", len(residues), BlockSize)
+	}
+}
+
+// TestVectorsPureGo checks the fallback against the reference vectors. The
+// fallback only uses explicit byte orders, so this must pass on big-endian
+// architectures too.
+func TestVectorsPureGo(t *testing.T) {
+	testdata := LoadTestData(t)
+	for _, v := range testdata.TestVectors {
+		AssertBytesEqual(t, v.Hash, checksumPureGo(v.Seed, v.Input))
+	}
+}
+
 func TestVectorsChecksum64(t *testing.T) {
 	testdata := LoadTestData(t)
 	for _, v := range testdata.TestVectors {
@@ -32,6 +84,267 @@ func TestVectorsChecksum32(t *testing.T) {
 	}
 }
 
+// TestVectorsTruncate confirms the reference 32 and 64-bit variants are the
+// leading little-endian lanes of the 128-bit hash, not a fold of it.
+func TestVectorsTruncate(t *testing.T) {
+	testdata := LoadTestData(t)
+	for _, v := range testdata.TestVectors {
+		if lane := binary.LittleEndian.Uint32(v.Hash); lane != v.Hash32 {
+			t.Fatalf("hash=%x hash32=%08x", v.Hash, v.Hash32)
+		}
+		if lane := binary.LittleEndian.Uint64(v.Hash); lane != v.Hash64 {
+			t.Fatalf("hash=%x hash64=%016x", v.Hash, v.Hash64)
+		}
+	}
+}
+
+func TestChecksum128(t *testing.T) {
+	cases := []struct {
+		Seed   uint64
+		Input  string
+		Hi, Lo uint64
+	}{
+		{0, "", 0xe749161e6c245401, 0x427ca137f5371628},
+		{0, "Hello, World!", 0x86531327feaa07f0, 0x8edaead7aab4cfa8},
+		{42, "Hello, World!", 0x420ec22ec9390e6a, 0xeb46284aed99e684},
+		{0xdeadbeef, "0123456789abcdef", 0xcbf97180d131bc8c, 0x4758daffe3dc233d},
+	}
+	for _, c := range cases {
+		hi, lo := Checksum128(c.Seed, []byte(c.Input))
+		if hi != c.Hi || lo != c.Lo {
+			t.Errorf("Checksum128(%#x, %q) got=%016x:%016x expect=%016x:%016x", c.Seed, c.Input, hi, lo, c.Hi, c.Lo)
+		}
+	}
+}
+
+func TestChecksumString(t *testing.T) {
+	testdata := LoadTestData(t)
+	for _, v := range testdata.TestVectors {
+		sum := ChecksumString(v.Seed, string(v.Input))
+		AssertBytesEqual(t, v.Hash, sum[:])
+		if sum64 := ChecksumString64(v.Seed, string(v.Input)); sum64 != v.Hash64 {
+			t.Fatalf("got=%016x expect%016x", sum64, v.Hash64)
+		}
+	}
+
+	s := strings.Repeat("map key ", 100)
+	allocs := testing.AllocsPerRun(100, func() {
+		ChecksumString(0, s)
+		ChecksumString64(0, s)
+	})
+	if allocs != 0 {
+		t.Errorf("ChecksumString allocs=%v expect=0", allocs)
+	}
+}
+
+func TestChecksumStrings(t *testing.T) {
+	parts := []string{"user", ":", "", "1234", strings.Repeat("x", BlockSize+3)}
+	expect := Checksum(5, []byte(strings.Join(parts, "")))
+	if got := ChecksumStrings(5, parts...); got != expect {
+		t.Fatalf("got=%x expect=%x", got, expect)
+	}
+	if got, expect := ChecksumStrings(5), Checksum(5, nil); got != expect {
+		t.Fatalf("no parts: got=%x expect=%x", got, expect)
+	}
+	if ChecksumStrings(5, "a", "bc") != ChecksumStrings(5, "ab", "c") {
+		t.Fatal("expected groupings of the same bytes to collide")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		ChecksumStrings(5, parts...)
+		ChecksumStringsPrefixed(5, parts...)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs=%v expect=0", allocs)
+	}
+}
+
+func TestChecksumStringsPrefixed(t *testing.T) {
+	var data []byte
+	parts := []string{"a", "", "bc"}
+	for _, p := range parts {
+		var n [8]byte
+		binary.LittleEndian.PutUint64(n[:], uint64(len(p)))
+		data = append(data, n[:]...)
+		data = append(data, p...)
+	}
+	if got, expect := ChecksumStringsPrefixed(5, parts...), Checksum(5, data); got != expect {
+		t.Fatalf("got=%x expect=%x", got, expect)
+	}
+
+	// Every grouping of the same bytes has a distinct checksum.
+	groupings := [][]string{
+		{"abc"},
+		{"a", "bc"},
+		{"ab", "c"},
+		{"a", "b", "c"},
+		{"", "abc"},
+		{"abc", ""},
+		{"a", "", "bc"},
+		{"", "", "abc"},
+	}
+	seen := map[[Size]byte][]string{}
+	for _, g := range groupings {
+		sum := ChecksumStringsPrefixed(5, g...)
+		if prev, ok := seen[sum]; ok {
+			t.Errorf("%q and %q collide", prev, g)
+		}
+		seen[sum] = g
+	}
+}
+
+func TestAppendChecksum(t *testing.T) {
+	frame := []byte("binary protocol frame")
+	got := AppendChecksum(append([]byte{}, frame...), 0, frame)
+	expect := Checksum(0, frame)
+	if !bytes.Equal(got[:len(frame)], frame) {
+		t.Fatalf("prefix modified: %x", got)
+	}
+	AssertBytesEqual(t, expect[:], got[len(frame):])
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendChecksum(buf[:0], 0, frame)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendChecksum allocs=%v expect=0", allocs)
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	data := make([]byte, 9*BlockSize)
+	rand.Read(data)
+	for n := 0; n <= len(data); n += BlockSize {
+		streams := make([]byte, BlockSize)
+		for i := 0; i < n; i += 3 * BlockSize {
+			end := i + 3*BlockSize
+			if end > n {
+				end = n
+			}
+			Blocks(streams, data[i:end])
+		}
+		trail := make([]byte, aes.BlockSize)
+		if n > 0 {
+			copy(trail, data[n-aes.BlockSize:n])
+		}
+		got := finish(3, streams, nil, trail, uint64(n))
+		if expect := Checksum(3, data[:n]); got != expect {
+			t.Errorf("length %d: got=%x expect=%x", n, got, expect)
+		}
+	}
+
+	for _, c := range []struct{ Streams, Block int }{{BlockSize - 1, BlockSize}, {BlockSize, 1}, {0, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("streams=%d block=%d: expected panic", c.Streams, c.Block)
+				}
+			}()
+			Blocks(make([]byte, c.Streams), make([]byte, c.Block))
+		}()
+	}
+}
+
+func TestAppendHex(t *testing.T) {
+	data := []byte("Hello, World!")
+	got := AppendHex([]byte("etag="), 0, data)
+	if string(got) != "etag=a8cfb4aad7eada8ef007aafe27135386" {
+		t.Fatalf("got=%s", got)
+	}
+	got = AppendHexUpper(nil, 0, data)
+	if string(got) != "A8CFB4AAD7EADA8EF007AAFE27135386" {
+		t.Fatalf("got=%s", got)
+	}
+
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendHex(buf[:0], 0, data)
+		buf = AppendHexUpper(buf, 0, data)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendHex allocs=%v expect=0", allocs)
+	}
+}
+
+func TestChecksumBuffers(t *testing.T) {
+	data := make([]byte, 3*BlockSize+40)
+	rand.Read(data)
+	splits := [][]int{
+		{},
+		{0},
+		{BlockSize},
+		{1, BlockSize - 1, BlockSize + 1},
+		{15, 16, 17, 2 * BlockSize},
+		{BlockSize - 16, BlockSize, BlockSize, 3 * BlockSize},
+	}
+	for _, split := range splits {
+		var bufs net.Buffers
+		prev := 0
+		for _, i := range split {
+			bufs = append(bufs, data[prev:i])
+			prev = i
+		}
+		bufs = append(bufs, data[prev:])
+
+		if got, expect := ChecksumBuffers(2, bufs), Checksum(2, data); got != expect {
+			t.Errorf("split at %v: got=%x expect=%x", split, got, expect)
+		}
+	}
+
+	bufs := net.Buffers{data[:20], data[20 : BlockSize+7], data[BlockSize+7:]}
+	allocs := testing.AllocsPerRun(100, func() {
+		ChecksumBuffers(2, bufs)
+		ConcatChecksum(2, bufs...)
+	})
+	if allocs != 0 {
+		t.Errorf("allocs=%v expect=0", allocs)
+	}
+}
+
+func TestConcatChecksum(t *testing.T) {
+	data := make([]byte, 2*BlockSize+3)
+	rand.Read(data)
+	cases := [][][]byte{
+		nil,
+		{nil},
+		{{}, {}},
+		{data},
+		{nil, data, nil},
+		{data[:1], data[1:BlockSize], {}, data[BlockSize:]},
+		{data[:BlockSize+7], nil, data[BlockSize+7:]},
+	}
+	for _, parts := range cases {
+		joined := bytes.Join(parts, nil)
+		if got, expect := ConcatChecksum(7, parts...), Checksum(7, joined); got != expect {
+			t.Errorf("%d parts of total length %d: got=%x expect=%x", len(parts), len(joined), got, expect)
+		}
+	}
+}
+
+func TestChecksumTo(t *testing.T) {
+	data := []byte("ChecksumTo writes directly to the destination")
+	dst := make([]byte, Size)
+	expect := Checksum(0, data)
+	ChecksumTo(0, dst, data)
+	AssertBytesEqual(t, expect[:], dst)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		ChecksumTo(0, dst, data)
+	})
+	if allocs != 0 {
+		t.Errorf("ChecksumTo allocs=%v expect=0", allocs)
+	}
+}
+
+func TestChecksumToPanicsOnShortDestination(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	ChecksumTo(0, make([]byte, 8), nil)
+}
+
 func TestVectorsHash(t *testing.T) {
 	testdata := LoadTestData(t)
 	for _, v := range testdata.TestVectors {
@@ -63,10 +376,616 @@ func TestVectorsHash32(t *testing.T) {
 	}
 }
 
+// TestKnownAnswers pins checksums for seeds with high bits set, computed on
+// amd64. The pure Go fallback must produce the same results on every
+// architecture, including 32-bit ones such as 386.
+func TestKnownAnswers(t *testing.T) {
+	cases := []struct {
+		Seed   uint64
+		Length int
+		Hash   string
+	}{
+		{0xffffffffffffffff, 0, "12d979a431dd71c0a84fd524d0dbcd9b"},
+		{0xffffffffffffffff, 15, "1abdaf1e8c48824c759fc597a055ba78"},
+		{0xffffffffffffffff, 16, "937f1444052b10b7d4c036a67b58bcdd"},
+		{0xffffffffffffffff, 300, "ac945ee8970355b9a103a9d15920735b"},
+		{0xffffffffffffffff, 4097, "14f503cbe6275634bbdb39fb29642bc0"},
+		{0x8000000000000001, 0, "1318fb9c94059e8af64909d5bd3bb9bd"},
+		{0x8000000000000001, 15, "6cfcff18048c2a3892c8830a0e51cdc4"},
+		{0x8000000000000001, 16, "e44970ede44b1a44455282cddb56c961"},
+		{0x8000000000000001, 300, "26a3847ff1bdb2cbc88f2d2e2ff1436f"},
+		{0x8000000000000001, 4097, "8803e75797a20182492819af1ff64fe5"},
+		{0x00000001ffffffff, 0, "372da8fcc2a5d5575e5c889441a02ba0"},
+		{0x00000001ffffffff, 15, "818192e25fa3f6cbbf683b65f6a6656e"},
+		{0x00000001ffffffff, 16, "3ab8165c66477691de51b5bc683fb462"},
+		{0x00000001ffffffff, 300, "772f25ddee14698b25d916d01d851ef6"},
+		{0x00000001ffffffff, 4097, "db1c67f6a612f1f7761501be259e6e2a"},
+	}
+	for _, c := range cases {
+		data := make([]byte, c.Length)
+		for i := range data {
+			data[i] = byte(i*7 + 3)
+		}
+		expect, err := hex.DecodeString(c.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sum := Checksum(c.Seed, data)
+		AssertBytesEqual(t, expect, sum[:])
+		if got := Checksum64(c.Seed, data); got != binary.LittleEndian.Uint64(expect) {
+			t.Errorf("seed=%016x length=%d: Checksum64=%016x", c.Seed, c.Length, got)
+		}
+
+		h := New(c.Seed)
+		for p := data; len(p) > 0; {
+			n := 7
+			if n > len(p) {
+				n = len(p)
+			}
+			h.Write(p[:n])
+			p = p[n:]
+		}
+		AssertBytesEqual(t, expect, h.Sum(nil))
+	}
+}
+
+// emptyVectors are reference checksums of the empty input, taken from
+// testdata/testvectors.json.
+var emptyVectors = []struct {
+	Seed uint64
+	Hash string
+}{
+	{0x41a73af1acd90c2a, "a5353de964b964af7134c72b3451420a"},
+	{0xf3cfbd6cff872983, "e2b1a5520744926b1be6876481edbfb1"},
+}
+
+func TestEmptyInput(t *testing.T) {
+	for _, v := range emptyVectors {
+		paths := map[string][Size]byte{
+			"Checksum(nil)":      Checksum(v.Seed, nil),
+			"Checksum([]byte{})": Checksum(v.Seed, []byte{}),
+			"ChecksumString":     ChecksumString(v.Seed, ""),
+			"SumOf":              SumOf(v.Seed, nil),
+			"checksumgo":         checksumgo(v.Seed, nil),
+		}
+		var sum [Size]byte
+		copy(sum[:], New(v.Seed).Sum(nil))
+		paths["Digest"] = sum
+		h := New(v.Seed)
+		h.Write(nil)
+		h.WriteString("")
+		copy(sum[:], h.Sum(nil))
+		paths["Digest with empty writes"] = sum
+
+		for name, got := range paths {
+			if s := hex.EncodeToString(got[:]); s != v.Hash {
+				t.Errorf("%s seed=%016x got=%s expect=%s", name, v.Seed, s, v.Hash)
+			}
+		}
+	}
+}
+
+var (
+	_ hash.Hash   = New(0)
+	_ hash.Hash32 = New32(0)
+	_ hash.Hash64 = New64(0)
+)
+
+func TestHashFactories(t *testing.T) {
+	b := []byte("factories for hash.Hash consumers")
+	cases := []struct {
+		Name string
+		New  func() hash.Hash
+		Seed uint64
+	}{
+		{"NewFunc", NewFunc(42), 42},
+		{"Hash", Hash, 0},
+	}
+	for _, c := range cases {
+		h := c.New()
+		h.Write(b)
+		expect := Checksum(c.Seed, b)
+		AssertBytesEqual(t, expect[:], h.Sum(nil))
+		if c.New() == h {
+			t.Errorf("%s returned the same hash twice", c.Name)
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	data := []byte("selected by name")
+	expect := Checksum(6, data)
+	for name, size := range map[string]int{"meow": 16, "meow64": 8, "meow32": 4} {
+		h, err := ByName(name, 6)
+		if err != nil {
+			t.Fatalf("ByName(%q): %v", name, err)
+		}
+		h.Write(data)
+		AssertBytesEqual(t, expect[:size], h.Sum(nil))
+		AssertHashSize(t, name, h, size)
+	}
+
+	for _, name := range []string{"", "Meow", "meow128", "sha256"} {
+		if h, err := ByName(name, 0); err == nil || h != nil {
+			t.Errorf("ByName(%q) expected error", name)
+		}
+	}
+}
+
 func TestHashSizes(t *testing.T) {
 	AssertHashSize(t, "New", New(0), Size)
 	AssertHashSize(t, "New64", New64(0), 8)
 	AssertHashSize(t, "New32", New32(0), 4)
+	AssertHashSize(t, "NewWithSize(6)", NewWithSize(0, 6), 6)
+	AssertHashSize(t, "NewWithSize(12)", NewWithSize(0, 12), 12)
+}
+
+func TestNewWithSizeTruncates(t *testing.T) {
+	b := []byte("truncated hashes are a prefix of the full hash")
+	expect := Checksum(0, b)
+	for _, size := range []int{6, 12} {
+		h := NewWithSize(0, size)
+		h.Write(b)
+		AssertBytesEqual(t, expect[:size], h.Sum(nil))
+	}
+}
+
+func TestNewWithSizeInvalid(t *testing.T) {
+	constructors := map[string]func(size int){
+		"new":            func(size int) { new(0, size) },
+		"NewWithSize":    func(size int) { NewWithSize(0, size) },
+		"NewWithOptions": func(size int) { NewWithOptions(WithSize(size)) },
+	}
+	for name, construct := range constructors {
+		for _, size := range []int{0, Size + 1, -1} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s(%d) expected panic", name, size)
+					}
+				}()
+				construct(size)
+			}()
+		}
+	}
+}
+
+func TestSumIntegersIgnoreSize(t *testing.T) {
+	b := []byte("Sum32 and Sum64 do not depend on the digest size")
+	expect64 := Checksum64(0, b)
+	expect32 := Checksum32(0, b)
+	for name, h := range map[string]*Digest{"New": New(0), "New64": New64(0), "New32": New32(0)} {
+		h.Write(b)
+		if sum := h.Sum64(); sum != expect64 {
+			t.Errorf("%s Sum64() got=%016x expect=%016x", name, sum, expect64)
+		}
+		if sum := h.Sum32(); sum != expect32 {
+			t.Errorf("%s Sum32() got=%08x expect=%08x", name, sum, expect32)
+		}
+	}
+}
+
+func TestWriteDoesNotRetainInput(t *testing.T) {
+	b := []byte("the trailing block must be copied out of the caller's buffer")
+	expect := Checksum(0, b)
+
+	buf := append([]byte(nil), b...)
+	h := New(0)
+	h.Write(buf)
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	AssertBytesEqual(t, expect[:], h.Sum(nil))
+}
+
+// TestWriteReusedBuffer writes from one buffer that is overwritten between
+// calls, as bufio.Reader and io.Copy do, and checks no write path retains it.
+func TestWriteReusedBuffer(t *testing.T) {
+	buf := make([]byte, 3*BlockSize)
+	for trial := 0; trial < Trials(); trial++ {
+		var written []byte
+		h := New(0)
+		for i := 0; i < 50; i++ {
+			// Lengths favor sub-block and sub-AES-block writes.
+			var n int
+			switch rand.Intn(3) {
+			case 0:
+				n = rand.Intn(aes.BlockSize + 1)
+			case 1:
+				n = rand.Intn(BlockSize + 1)
+			default:
+				n = rand.Intn(len(buf) + 1)
+			}
+			rand.Read(buf[:n])
+			h.Write(buf[:n])
+			written = append(written, buf[:n]...)
+
+			// Scribble over the buffer before the next write.
+			for j := range buf {
+				buf[j] = ^buf[j]
+			}
+			if i%10 == 0 {
+				expect := Checksum(0, written)
+				AssertBytesEqual(t, expect[:], h.Sum(nil))
+			}
+		}
+		expect := Checksum(0, written)
+		AssertBytesEqual(t, expect[:], h.Sum(nil))
+	}
+}
+
+// TestTrailingBlockInvariant confirms the trailing block always holds the
+// last min(16, length) bytes written, right aligned, with zeros before them.
+func TestTrailingBlockInvariant(t *testing.T) {
+	for trial := 0; trial < Trials(); trial++ {
+		var written []byte
+		h := New(0)
+		for i := 0; i < 40; i++ {
+			n := rand.Intn(2*aes.BlockSize + 1)
+			if rand.Intn(4) == 0 {
+				n = rand.Intn(2*BlockSize + 1)
+			}
+			p := make([]byte, n)
+			rand.Read(p)
+			if n == 1 && rand.Intn(2) == 0 {
+				h.WriteByte(p[0])
+			} else {
+				h.Write(p)
+			}
+			written = append(written, p...)
+
+			k := len(written)
+			if k > aes.BlockSize {
+				k = aes.BlockSize
+			}
+			var expect [aes.BlockSize]byte
+			copy(expect[aes.BlockSize-k:], written[len(written)-k:])
+			if h.t != expect {
+				t.Fatalf("after %d bytes: trailing block %x expect %x", len(written), h.t, expect)
+			}
+		}
+	}
+}
+
+func TestWriteLengthOverflow(t *testing.T) {
+	h := New(0)
+	h.Write([]byte("abc"))
+	h.length = math.MaxUint64 - 1
+	h.Write([]byte{1})
+	if h.BytesWritten() != math.MaxUint64 {
+		t.Fatalf("BytesWritten()=%d", h.BytesWritten())
+	}
+	h.Write(nil)
+
+	before := *h
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic writing past 2^64-1 bytes")
+			}
+		}()
+		h.Write([]byte{2})
+	}()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic writing a byte past 2^64-1 bytes")
+			}
+		}()
+		h.WriteByte(2)
+	}()
+	if *h != before {
+		t.Fatal("Digest modified by overflowing Write")
+	}
+
+	h.Reset()
+	h.Write([]byte{2})
+	if h.BytesWritten() != 1 {
+		t.Fatalf("BytesWritten()=%d after Reset", h.BytesWritten())
+	}
+}
+
+func TestClone(t *testing.T) {
+	prefix := bytes.Repeat([]byte("shared prefix "), 20)
+	a := []byte("followed by one suffix")
+	b := []byte("and another")
+
+	h := New(0)
+	h.Write(prefix)
+	c := h.Clone()
+	h.Write(a)
+	c.Write(b)
+
+	expect := Checksum(0, append(append([]byte{}, prefix...), a...))
+	AssertBytesEqual(t, expect[:], h.Sum(nil))
+	expect = Checksum(0, append(append([]byte{}, prefix...), b...))
+	AssertBytesEqual(t, expect[:], c.Sum(nil))
+}
+
+func TestSum128(t *testing.T) {
+	testdata := LoadTestData(t)
+	for _, v := range testdata.TestVectors {
+		h := New32(v.Seed)
+		h.Write(v.Input)
+		hi, lo := h.Sum128()
+		if lo != binary.LittleEndian.Uint64(v.Hash[:8]) || hi != binary.LittleEndian.Uint64(v.Hash[8:]) {
+			t.Fatalf("got=%016x:%016x expect=%x", hi, lo, v.Hash)
+		}
+	}
+}
+
+func TestSumInto(t *testing.T) {
+	data := make([]byte, BlockSize+40)
+	rand.Read(data)
+	expect := Checksum(2, data)
+	for _, h := range []*Digest{New(2), New32(2)} {
+		h.Write(data)
+		before := *h
+		var sum [Size]byte
+		h.SumInto(&sum)
+		if sum != expect {
+			t.Fatalf("size %d: got=%x expect=%x", h.Size(), sum, expect)
+		}
+		if *h != before {
+			t.Fatal("SumInto modified the Digest")
+		}
+	}
+
+	h := New(2)
+	h.Write(data)
+	var sum [Size]byte
+	h.SumInto(&sum)
+	AssertBytesEqual(t, h.Sum(nil), sum[:])
+	allocs := testing.AllocsPerRun(100, func() {
+		h.SumInto(&sum)
+	})
+	if allocs != 0 {
+		t.Errorf("SumInto allocs=%v expect=0", allocs)
+	}
+}
+
+// TestSumFamily confirms every way of finishing a Digest yields the same hash
+// for randomized states.
+func TestSumFamily(t *testing.T) {
+	for trial := 0; trial < Trials(); trial++ {
+		data := make([]byte, rand.Intn(4*BlockSize))
+		rand.Read(data)
+		h := New(rand.Uint64())
+		for p := data; len(p) > 0; {
+			n := rand.Intn(len(p) + 1)
+			h.Write(p[:n])
+			p = p[n:]
+		}
+		expect := Checksum(h.Seed(), data)
+
+		prefix := []byte("existing")
+		appended := h.Sum(append([]byte(nil), prefix...))
+		dst := make([]byte, Size)
+		h.SumTo(dst)
+		var into [Size]byte
+		h.SumInto(&into)
+		sum16 := h.Sum16()
+		hi, lo := h.Sum128()
+		var words [Size]byte
+		binary.LittleEndian.PutUint64(words[:8], lo)
+		binary.LittleEndian.PutUint64(words[8:], hi)
+
+		results := map[string][]byte{
+			"Sum(nil)":      h.Sum(nil),
+			"Sum(existing)": appended[len(prefix):],
+			"SumTo":         dst,
+			"SumInto":       into[:],
+			"Sum16":         sum16[:],
+			"Sum128":        words[:],
+		}
+		if !bytes.Equal(appended[:len(prefix)], prefix) {
+			t.Fatalf("Sum(existing) modified the prefix: %q", appended[:len(prefix)])
+		}
+		for name, got := range results {
+			if !bytes.Equal(got, expect[:]) {
+				t.Fatalf("%s length %d: got=%x expect=%x", name, len(data), got, expect)
+			}
+		}
+		if h.Sum64() != binary.LittleEndian.Uint64(expect[:8]) || h.Sum32() != binary.LittleEndian.Uint32(expect[:4]) {
+			t.Fatalf("length %d: Sum64=%016x Sum32=%08x expect=%x", len(data), h.Sum64(), h.Sum32(), expect)
+		}
+	}
+}
+
+func TestSumAppendsInPlace(t *testing.T) {
+	data := make([]byte, 2*BlockSize+9)
+	rand.Read(data)
+	expect := Checksum(0, data)
+	h := New(0)
+	h.Write(data)
+	before := *h
+
+	buf := make([]byte, 4, 64)
+	copy(buf, "abcd")
+	got := h.Sum(buf)
+	if &got[0] != &buf[0] {
+		t.Fatal("Sum reallocated a buffer with enough capacity")
+	}
+	if string(got[:4]) != "abcd" {
+		t.Fatalf("Sum modified the existing contents: %q", got[:4])
+	}
+	AssertBytesEqual(t, expect[:], got[4:])
+	if *h != before {
+		t.Fatal("Sum modified the Digest")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = h.Sum(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("Sum(buf[:0]) allocs=%v expect=0", allocs)
+	}
+}
+
+func TestSum64Allocs(t *testing.T) {
+	data := make([]byte, 128)
+	rand.Read(data)
+	h := New64(0)
+	allocs := testing.AllocsPerRun(100, func() {
+		h.Reset()
+		h.Write(data)
+		h.Sum64()
+	})
+	if allocs != 0 {
+		t.Errorf("Sum64 allocs=%v expect=0", allocs)
+	}
+	if got, expect := h.Sum64(), Checksum64(0, data); got != expect {
+		t.Fatalf("got=%016x expect=%016x", got, expect)
+	}
+}
+
+func TestSum16(t *testing.T) {
+	testdata := LoadTestData(t)
+	for _, v := range testdata.TestVectors {
+		h := New32(v.Seed)
+		h.Write(v.Input)
+		sum := h.Sum16()
+		AssertBytesEqual(t, v.Hash, sum[:])
+	}
+
+	h := New(0)
+	h.Write(make([]byte, BlockSize+5))
+	before := *h
+	allocs := testing.AllocsPerRun(100, func() {
+		h.Sum16()
+	})
+	if allocs != 0 {
+		t.Errorf("Sum16 allocs=%v expect=0", allocs)
+	}
+	if *h != before {
+		t.Fatal("Sum16 modified the Digest")
+	}
+}
+
+func TestWriteString(t *testing.T) {
+	s := strings.Repeat("WriteString avoids converting to []byte. ", 10)
+	h := New(0)
+	for _, part := range strings.SplitAfter(s, " ") {
+		h.WriteString(part)
+	}
+	expect := Checksum(0, []byte(s))
+	AssertBytesEqual(t, expect[:], h.Sum(nil))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		h.WriteString(s)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteString allocs=%v expect=0", allocs)
+	}
+}
+
+var _ io.ByteWriter = New(0)
+
+func TestWriteByte(t *testing.T) {
+	data := make([]byte, 3*BlockSize+17)
+	rand.Read(data)
+	h := New(0)
+	for n := 0; n <= len(data); n++ {
+		expect := Checksum(0, data[:n])
+		AssertBytesEqual(t, expect[:], h.Sum(nil))
+		if n < len(data) {
+			if err := h.WriteByte(data[n]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Mixed with Write.
+	h.Reset()
+	h.Write(data[:5])
+	h.WriteByte(data[5])
+	h.Write(data[6:])
+	expect := Checksum(0, data)
+	AssertBytesEqual(t, expect[:], h.Sum(nil))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		h.WriteByte(1)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteByte allocs=%v expect=0", allocs)
+	}
+}
+
+// TestStreamingAllocs codifies the allocation contract of the streaming API:
+// once a Digest exists, writing and SumTo allocate nothing, and Sum(nil)
+// allocates only the returned slice.
+func TestStreamingAllocs(t *testing.T) {
+	data := make([]byte, 16*BlockSize)
+	rand.Read(data)
+	sum := make([]byte, Size)
+
+	h := New(0)
+	allocs := testing.AllocsPerRun(100, func() {
+		h.Reset()
+		for i := 0; i < len(data); i += BlockSize {
+			h.Write(data[i : i+BlockSize])
+		}
+		h.Write(data[:BlockSize*4])
+		h.SumTo(sum)
+	})
+	if allocs != 0 {
+		t.Errorf("Write and SumTo allocs=%v expect=0", allocs)
+	}
+
+	allocs = testing.AllocsPerRun(100, func() {
+		h := New(0)
+		h.Write(data)
+		h.SumTo(sum)
+	})
+	if allocs > 1 {
+		t.Errorf("New, Write and SumTo allocs=%v expect at most 1", allocs)
+	}
+
+	allocs = testing.AllocsPerRun(100, func() {
+		sum = h.Sum(nil)
+	})
+	if allocs != 1 {
+		t.Errorf("Sum(nil) allocs=%v expect=1", allocs)
+	}
+}
+
+func TestReadFrom(t *testing.T) {
+	data := make([]byte, 10<<20)
+	rand.Read(data)
+	expect := Checksum(0, data)
+
+	h := New(0)
+	n, err := io.Copy(h, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) {
+		t.Fatalf("read %d bytes expect=%d", n, len(data))
+	}
+	AssertBytesEqual(t, expect[:], h.Sum(nil))
+
+	// Short reads, and data returned alongside EOF.
+	h.Reset()
+	r := iotest.DataErrReader(iotest.HalfReader(bytes.NewReader(data[:5000])))
+	if _, err := h.ReadFrom(r); err != nil {
+		t.Fatal(err)
+	}
+	expect = Checksum(0, data[:5000])
+	AssertBytesEqual(t, expect[:], h.Sum(nil))
+}
+
+func TestReadFromError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 1000)), iotest.ErrReader(errRead))
+	n, err := New(0).ReadFrom(r)
+	if err != errRead {
+		t.Fatalf("got error %v expect %v", err, errRead)
+	}
+	if n != 1000 {
+		t.Fatalf("read %d bytes expect=1000", n)
+	}
 }
 
 func TestSumTo(t *testing.T) {
@@ -83,6 +1002,104 @@ func TestSumTo(t *testing.T) {
 	}
 }
 
+func TestSumToSizes(t *testing.T) {
+	b := []byte("SumTo writes exactly Size() bytes")
+	for name, h := range map[string]*Digest{"New": New(0), "New64": New64(0), "New32": New32(0)} {
+		h.Write(b)
+		expect := h.Sum(nil)
+		dst := bytes.Repeat([]byte{0xff}, Size)
+		h.SumTo(dst)
+		if !bytes.Equal(dst[:h.Size()], expect) {
+			t.Errorf("%s SumTo() got=%x expect=%x", name, dst[:h.Size()], expect)
+		}
+		for _, c := range dst[h.Size():] {
+			if c != 0xff {
+				t.Errorf("%s SumTo() wrote beyond Size(): %x", name, dst)
+				break
+			}
+		}
+		got := make([]byte, h.Size())
+		h.SumTo(got)
+		if !bytes.Equal(got, expect) {
+			t.Errorf("%s SumTo() changed hash state", name)
+		}
+	}
+}
+
+func TestSumToN(t *testing.T) {
+	data := []byte("one digest, several truncations")
+	expect := Checksum(3, data)
+	h := New32(3)
+	h.Write(data)
+	for _, n := range []int{4, 8, 12, 16} {
+		dst := make([]byte, Size+1)
+		h.SumToN(dst, n)
+		AssertBytesEqual(t, expect[:n], dst[:n])
+		if !bytes.Equal(dst[n:], make([]byte, Size+1-n)) {
+			t.Errorf("n=%d: wrote past n bytes: %x", n, dst)
+		}
+	}
+
+	cases := []struct{ Dst, N int }{{16, 0}, {16, -1}, {17, 17}, {3, 4}}
+	for _, c := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("len(dst)=%d n=%d: expected panic", c.Dst, c.N)
+				}
+			}()
+			h.SumToN(make([]byte, c.Dst), c.N)
+		}()
+	}
+}
+
+// failWriter is an io.Writer that always fails with err.
+type failWriter struct {
+	err error
+}
+
+func (w failWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestWriteTo(t *testing.T) {
+	for _, h := range []*Digest{New(1), New64(1), NewWithSize(1, 5)} {
+		h.Write([]byte("piped out"))
+		before := *h
+		var buf bytes.Buffer
+		buf.WriteString("sum:")
+		n, err := h.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(h.Size()) {
+			t.Errorf("wrote %d bytes expect %d", n, h.Size())
+		}
+		AssertBytesEqual(t, append([]byte("sum:"), h.Sum(nil)...), buf.Bytes())
+		if *h != before {
+			t.Fatal("WriteTo modified the Digest")
+		}
+	}
+
+	h := New(1)
+	if n, err := h.WriteTo(shortWriter{n: 3}); n != 3 || err != io.ErrShortWrite {
+		t.Errorf("short write: n=%d err=%v", n, err)
+	}
+	errWrite := errors.New("write failed")
+	if _, err := h.WriteTo(failWriter{errWrite}); err != errWrite {
+		t.Errorf("failed write: got error %v expect %v", err, errWrite)
+	}
+}
+
+func TestSumToPanicsOnShortDestination(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	New64(0).SumTo(make([]byte, 4))
+}
+
 func TestChecksumMatchesHash(t *testing.T) {
 	CheckEqual(t, checksumSlice, checksumHash)
 }
@@ -91,10 +1108,263 @@ func TestHashBatching(t *testing.T) {
 	CheckEqual(t, checksumHash, checksumRandomBatchedHash)
 }
 
+func TestHashSplitPoints(t *testing.T) {
+	maxLength := 4*BlockSize + 17
+	if testing.Short() {
+		maxLength = 2*BlockSize + 17
+	}
+	data := make([]byte, maxLength)
+	rand.Read(data)
+
+	// Offsets of the second split relative to the first, around the AES and
+	// Meow block sizes.
+	deltas := []int{0, 1, 15, 16, 17, BlockSize - 1, BlockSize, BlockSize + 1}
+
+	h := New(0)
+	for n := 0; n <= maxLength; n++ {
+		expect := Checksum(0, data[:n])
+		for i := 0; i <= n; i++ {
+			h.Reset()
+			h.Write(data[:i])
+			h.Write(data[i:n])
+			if got := h.Sum(nil); !bytes.Equal(got, expect[:]) {
+				t.Fatalf("length %d split at %d: got=%x expect=%x", n, i, got, expect)
+			}
+
+			for _, delta := range deltas {
+				j := i + delta
+				if j > n {
+					break
+				}
+				h.Reset()
+				h.Write(data[:i])
+				h.Write(data[i:j])
+				h.Write(data[j:n])
+				if got := h.Sum(nil); !bytes.Equal(got, expect[:]) {
+					t.Fatalf("length %d split at %d and %d: got=%x expect=%x", n, i, j, got, expect)
+				}
+			}
+		}
+	}
+}
+
+// TestWriteTopsOffPending covers writes that complete a partially filled
+// pending block and continue with further blocks and a tail.
+func TestWriteTopsOffPending(t *testing.T) {
+	data := make([]byte, 8*BlockSize)
+	rand.Read(data)
+	for _, pending := range []int{1, 15, 16, 17, BlockSize / 2, BlockSize - 1} {
+		for _, blocks := range []int{0, 1, 2, 5} {
+			for _, tail := range []int{0, 1, 16, BlockSize - 1} {
+				n := BlockSize + blocks*BlockSize + tail
+				h := New(0)
+				h.Write(data[:pending])
+				h.Write(data[pending:n])
+				if h.BytesWritten() != uint64(n) {
+					t.Fatalf("BytesWritten()=%d expect=%d", h.BytesWritten(), n)
+				}
+				if h.n != tail {
+					t.Errorf("pending=%d blocks=%d tail=%d: %d bytes pending", pending, blocks, tail, h.n)
+				}
+				expect := Checksum(0, data[:n])
+				if got := h.Sum(nil); !bytes.Equal(got, expect[:]) {
+					t.Errorf("pending=%d blocks=%d tail=%d: got=%x expect=%x", pending, blocks, tail, got, expect)
+				}
+			}
+		}
+	}
+}
+
+// blockBoundaryWrites returns ways of splitting n bytes into writes that land
+// exactly on BlockSize boundaries, with and without a pending partial block.
+func blockBoundaryWrites(n int) [][]int {
+	var aligned, offset []int
+	for i := BlockSize; i < n; i += BlockSize {
+		aligned = append(aligned, i)
+	}
+	offset = append(offset, 1)
+	for i := BlockSize; i < n; i += BlockSize {
+		offset = append(offset, i, i+1)
+	}
+	return [][]int{nil, aligned, offset}
+}
+
+// checkBlockBoundaries hashes data with each split of blockBoundaryWrites and
+// compares against expect.
+func checkBlockBoundaries(t *testing.T, seed uint64, data, expect []byte) {
+	t.Helper()
+	for _, split := range blockBoundaryWrites(len(data)) {
+		h := New(seed)
+		prev := 0
+		for _, i := range split {
+			if i > len(data) {
+				break
+			}
+			h.Write(data[prev:i])
+			prev = i
+		}
+		h.Write(data[prev:])
+		if got := h.Sum(nil); !bytes.Equal(got, expect) {
+			t.Errorf("length %d split at %v: got=%x expect=%x", len(data), split, got, expect)
+		}
+	}
+}
+
+// TestBlockBoundaries covers inputs that are exactly one or more blocks long,
+// or one byte off, written in one go or split at block boundaries.
+func TestBlockBoundaries(t *testing.T) {
+	// Reference vectors at the edge lengths, or within a byte of a block
+	// multiple.
+	edge := map[int]bool{}
+	for _, n := range edgeLengths {
+		edge[n] = true
+	}
+	testdata := LoadTestData(t)
+	found := 0
+	for _, v := range testdata.TestVectors {
+		if edge[len(v.Input)] {
+			found++
+		}
+		r := len(v.Input) % BlockSize
+		if edge[len(v.Input)] || (len(v.Input) >= BlockSize && (r <= 1 || r == BlockSize-1)) {
+			AssertBytesEqual(t, v.Hash, checksumSlice(v.Seed, v.Input))
+			AssertBytesEqual(t, v.Hash, checksumPureGo(v.Seed, v.Input))
+			checkBlockBoundaries(t, v.Seed, v.Input, v.Hash)
+		}
+	}
+	if found == 0 {
+		t.Errorf("no reference vectors at the edge lengths %v", edgeLengths)
+	}
+
+	// Exact lengths, on the selected implementation and the pure Go fallback,
+	// in one go and split at block boundaries. These only check consistency:
+	// the expected values come from the reference vectors above.
+	for _, n := range []int{BlockSize, BlockSize + 1, 2 * BlockSize} {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		expect := checksumPureGo(0, data)
+		AssertBytesEqual(t, expect, checksumSlice(0, data))
+		checkBlockBoundaries(t, 0, data, expect)
+	}
+}
+
 func TestHashReset(t *testing.T) {
 	CheckEqual(t, checksumHash, checksumHashWithReset)
 }
 
+func TestAccessors(t *testing.T) {
+	h := New32(0x5eed)
+	if h.Seed() != 0x5eed {
+		t.Errorf("Seed()=%#x expect=0x5eed", h.Seed())
+	}
+
+	var total uint64
+	for _, n := range []int{0, 3, 300, 17} {
+		h.Write(make([]byte, n))
+		total += uint64(n)
+		if h.BytesWritten() != total {
+			t.Errorf("BytesWritten()=%d expect=%d", h.BytesWritten(), total)
+		}
+	}
+
+	h.Reset()
+	if h.BytesWritten() != 0 {
+		t.Errorf("BytesWritten()=%d after Reset", h.BytesWritten())
+	}
+	if h.Seed() != 0x5eed {
+		t.Errorf("Seed()=%#x after Reset", h.Seed())
+	}
+}
+
+// TestResetMatchesFresh confirms a reset Digest is identical to a freshly
+// constructed one, keeping its seed and size, for every constructor.
+func TestResetMatchesFresh(t *testing.T) {
+	constructors := map[string]func() *Digest{
+		"New":            func() *Digest { return New(11) },
+		"New64":          func() *Digest { return New64(11) },
+		"New32":          func() *Digest { return New32(11) },
+		"NewWithSize":    func() *Digest { return NewWithSize(11, 5) },
+		"NewWithOptions": func() *Digest { return NewWithOptions(WithSeed(11), WithSize(12)) },
+		"Zero":           func() *Digest { return &Digest{} },
+	}
+	first := make([]byte, 2*BlockSize+21)
+	second := make([]byte, BlockSize+3)
+	rand.Read(first)
+	rand.Read(second)
+	for name, construct := range constructors {
+		h := construct()
+		h.Write(first)
+		h.Reset()
+		if fresh := construct(); *h != *fresh {
+			t.Errorf("%s: reset digest differs from a fresh one", name)
+		}
+		h.Write(second)
+
+		fresh := construct()
+		fresh.Write(second)
+		AssertBytesEqual(t, fresh.Sum(nil), h.Sum(nil))
+		if h.Size() != fresh.Size() || h.Seed() != fresh.Seed() {
+			t.Errorf("%s: size=%d seed=%d expect size=%d seed=%d", name, h.Size(), h.Seed(), fresh.Size(), fresh.Seed())
+		}
+	}
+}
+
+func TestResetClearsPendingData(t *testing.T) {
+	h := New(0)
+	h.Write(bytes.Repeat([]byte("secret"), 100))
+	h.Reset()
+	if h.b != [BlockSize]byte{} {
+		t.Fatalf("pending block not cleared: %x", h.b)
+	}
+	if h.t != [len(h.t)]byte{} {
+		t.Fatalf("trailing block not cleared: %x", h.t)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	data := []byte("the zero value just works")
+	var d Digest
+	d.Write(data)
+
+	h := New(0)
+	h.Write(data)
+	if d.Size() != Size {
+		t.Fatalf("Size()=%d expect=%d", d.Size(), Size)
+	}
+	AssertBytesEqual(t, h.Sum(nil), d.Sum(nil))
+	sum := make([]byte, Size)
+	d.SumTo(sum)
+	AssertBytesEqual(t, h.Sum(nil), sum)
+	if d.Sum64() != h.Sum64() {
+		t.Fatalf("Sum64()=%016x expect=%016x", d.Sum64(), h.Sum64())
+	}
+
+	// The zero value round trips through marshaling as a 128-bit hash.
+	b, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored Digest
+	if err := restored.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	AssertBytesEqual(t, h.Sum(nil), restored.Sum(nil))
+}
+
+func TestZeroize(t *testing.T) {
+	h := New64(0x5ec7e7)
+	h.Write(bytes.Repeat([]byte("secret"), 100))
+	h.Zeroize()
+	if *h != (Digest{}) {
+		t.Fatalf("state not cleared: %+v", *h)
+	}
+	if h.Size() != Size {
+		t.Errorf("Size()=%d after Zeroize expect=%d", h.Size(), Size)
+	}
+}
+
 func TestHashSumPreservesState(t *testing.T) {
 	CheckEqual(t, checksumHash, checksumHashWithIntermediateSum)
 }
@@ -107,8 +1377,134 @@ func TestVersions(t *testing.T) {
 	if VersionName != testdata.VersionName {
 		t.Errorf("version name mismatch got=%s reference=%s", VersionName, testdata.VersionName)
 	}
+
+	var zero Digest
+	for _, h := range []*Digest{New(0), New32(1), NewWithOptions(WithSize(3)), &zero} {
+		if h.Version() != testdata.Version || h.VersionName() != testdata.VersionName {
+			t.Errorf("digest version %d %q expect %d %q", h.Version(), h.VersionName(), testdata.Version, testdata.VersionName)
+		}
+	}
+}
+
+// TestConcurrentHashing hashes from many goroutines at once, so that the race
+// detector checks the steady-state reads of the implementation variables.
+func TestConcurrentHashing(t *testing.T) {
+	data := make([]byte, 3*BlockSize+5)
+	rand.Read(data)
+	expect := Checksum(0, data)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if got := Checksum(0, data); got != expect {
+					t.Errorf("Checksum got=%x expect=%x", got, expect)
+				}
+				h := New(0)
+				h.Write(data[:100])
+				h.Write(data[100:])
+				if got := h.Sum(nil); !bytes.Equal(got, expect[:]) {
+					t.Errorf("Digest got=%x expect=%x", got, expect)
+				}
+				if got := ChecksumUint64(0, uint64(i)); got == 0 {
+					t.Errorf("ChecksumUint64 returned 0")
+				}
+			}
+		}()
+	}
+	wg.Wait()
 }
 
 func TestDisplayImplementation(t *testing.T) {
-	t.Logf("implementation=%s", implementation)
+	t.Logf("implementation=%s", Implementation())
+}
+
+func TestForcePureGo(t *testing.T) {
+	defer UseAccelerated()
+
+	ForcePureGo()
+	if Implementation() != "go" || finishAsm {
+		t.Fatalf("implementation=%s finishAsm=%v after ForcePureGo", Implementation(), finishAsm)
+	}
+	CheckEqual(t, checksumSlice, checksumPureGo)
+
+	accelerated := UseAccelerated()
+	if accelerated == (Implementation() == "go") || finishAsm != accelerated {
+		t.Fatalf("UseAccelerated()=%v with implementation=%s finishAsm=%v", accelerated, Implementation(), finishAsm)
+	}
+	CheckEqual(t, checksumSlice, checksumPureGo)
+}
+
+func TestChecksumShort(t *testing.T) {
+	defer UseAccelerated()
+
+	// One-shot entry points, with their result converted to a full checksum.
+	entries := []struct {
+		Name string
+		Sum  func(seed uint64, data []byte) [Size]byte
+	}{
+		{"Checksum", Checksum},
+		{"ChecksumString", func(seed uint64, data []byte) [Size]byte {
+			return ChecksumString(seed, string(data))
+		}},
+		{"ChecksumTo", func(seed uint64, data []byte) (sum [Size]byte) {
+			ChecksumTo(seed, sum[:], data)
+			return
+		}},
+		{"AppendChecksum", func(seed uint64, data []byte) (sum [Size]byte) {
+			copy(sum[:], AppendChecksum(nil, seed, data))
+			return
+		}},
+		{"AppendHex", func(seed uint64, data []byte) (sum [Size]byte) {
+			hex.Decode(sum[:], AppendHex(nil, seed, data))
+			return
+		}},
+		{"Checksum128", func(seed uint64, data []byte) (sum [Size]byte) {
+			hi, lo := Checksum128(seed, data)
+			binary.LittleEndian.PutUint64(sum[:8], lo)
+			binary.LittleEndian.PutUint64(sum[8:], hi)
+			return
+		}},
+		{"SumOf", func(seed uint64, data []byte) [Size]byte {
+			return SumOf(seed, data)
+		}},
+		{"Key", func(seed uint64, data []byte) [Size]byte {
+			return Key(seed, data).Bytes()
+		}},
+		{"ETag", func(seed uint64, data []byte) (sum [Size]byte) {
+			tag := ETag(seed, data)
+			hex.Decode(sum[:], []byte(tag[1:len(tag)-1]))
+			return
+		}},
+		{"ChecksumBatch", func(seed uint64, data []byte) [Size]byte {
+			return ChecksumBatch(seed, [][]byte{data})[0]
+		}},
+		{"checksumgo", checksumgo},
+	}
+
+	data := make([]byte, 2*BlockSize)
+	for i := range data {
+		data[i] = byte(i*7 + 1)
+	}
+	for _, pure := range []bool{false, true} {
+		if pure {
+			ForcePureGo()
+		}
+		for _, seed := range []uint64{0, 1, 0x0123456789abcdef, math.MaxUint64} {
+			for n := 0; n <= BlockSize+1; n++ {
+				expect := checksum(seed, data[:n])
+				for _, e := range entries {
+					if got := e.Sum(seed, data[:n]); got != expect {
+						t.Errorf("%s: %s(%#x, len=%d)=%x expect=%x", Implementation(), e.Name, seed, n, got, expect)
+					}
+				}
+				expect64 := binary.LittleEndian.Uint64(expect[:8])
+				if got := ChecksumString64(seed, string(data[:n])); got != expect64 {
+					t.Errorf("%s: ChecksumString64(%#x, len=%d)=%016x expect=%016x", Implementation(), seed, n, got, expect64)
+				}
+			}
+		}
+	}
 }
@@ -0,0 +1,71 @@
+//go:build meowref
+// +build meowref
+
+package meow
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+var meowrefPath = flag.String("meowref", "testdata/meowref", "reference harness built from testdata/meowref.cc")
+
+// TestReferenceInterop hashes random inputs with the reference implementation
+// and confirms this package agrees, with both the selected implementation and
+// the pure Go fallback.
+func TestReferenceInterop(t *testing.T) {
+	if _, err := os.Stat(*meowrefPath); err != nil {
+		t.Skipf("reference harness unavailable: %v", err)
+	}
+
+	cmd := exec.Command(*meowrefPath)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	w := bufio.NewWriter(stdin)
+
+	maxLengths := []int{1 << 6, 1 << 10, 1 << 16, 4 << 20}
+	for trial := 0; trial < Trials(); trial++ {
+		seed := rand.Uint64()
+		data := make([]byte, rand.Intn(maxLengths[trial%len(maxLengths)]+1))
+		rand.Read(data)
+
+		var header [16]byte
+		binary.LittleEndian.PutUint64(header[:8], seed)
+		binary.LittleEndian.PutUint64(header[8:], uint64(len(data)))
+		w.Write(header[:])
+		w.Write(data)
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		var expect [Size]byte
+		if _, err := io.ReadFull(stdout, expect[:]); err != nil {
+			t.Fatal(err)
+		}
+		if got := Checksum(seed, data); got != expect {
+			t.Fatalf("seed=%016x length=%d: got=%x reference=%x", seed, len(data), got, expect)
+		}
+		AssertBytesEqual(t, expect[:], checksumPureGo(seed, data))
+	}
+
+	stdin.Close()
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
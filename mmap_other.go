@@ -0,0 +1,11 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package meow
+
+// ChecksumMmap returns the Meow checksum of the contents of the named file.
+// Memory mapping is not supported on this platform, so it is equivalent to
+// ChecksumFile.
+func ChecksumMmap(seed uint64, path string) ([Size]byte, error) {
+	return ChecksumFile(seed, path)
+}
@@ -0,0 +1,41 @@
+package meow
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumMmap(t *testing.T) {
+	dir := t.TempDir()
+	for _, n := range []int{0, 1, BlockSize, 5<<20 + 3} {
+		data := make([]byte, n)
+		rand.Read(data)
+		path := filepath.Join(dir, "data")
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := ChecksumMmap(1, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect, err := ChecksumFile(1, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expect {
+			t.Errorf("length %d: got=%x expect=%x", n, got, expect)
+		}
+	}
+}
+
+func TestChecksumMmapNotExist(t *testing.T) {
+	_, err := ChecksumMmap(0, filepath.Join(t.TempDir(), "missing"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got error %v expect not exist", err)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package meow
+
+import (
+	"os"
+	"syscall"
+)
+
+// ChecksumMmap returns the Meow checksum of the contents of the named file,
+// hashing a memory mapping of the whole file in one call. This avoids copying
+// large files through a read buffer. Empty files, files that are not regular
+// such as pipes, and files that the filesystem refuses to map are read as in
+// ChecksumFile instead. Errors are of type *Error.
+//
+// The file must not be truncated while it is being hashed: accessing the
+// mapping beyond the new end of the file raises SIGBUS, which crashes the
+// program rather than returning an error.
+func ChecksumMmap(seed uint64, path string) ([Size]byte, error) {
+	var sum [Size]byte
+	f, err := os.Open(path)
+	if err != nil {
+		return sum, fileError("open", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return sum, fileError("stat", path, err)
+	}
+	size := info.Size()
+	if !info.Mode().IsRegular() || size == 0 || int64(int(size)) != size {
+		return checksumOpenFile(seed, path, f)
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return checksumOpenFile(seed, path, f)
+	}
+	defer syscall.Munmap(data)
+	return checksumOneShot(seed, data), nil
+}
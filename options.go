@@ -0,0 +1,31 @@
+package meow
+
+// Option configures a Digest created by NewWithOptions.
+type Option func(*options)
+
+// options is the configuration of a Digest.
+type options struct {
+	seed uint64
+	size int
+}
+
+// WithSeed sets the hash seed. The default seed is 0.
+func WithSeed(seed uint64) Option {
+	return func(o *options) { o.seed = seed }
+}
+
+// WithSize sets the digest size in bytes, truncating the 128-bit hash as in
+// NewWithSize. The default size is 16.
+func WithSize(size int) Option {
+	return func(o *options) { o.size = size }
+}
+
+// NewWithOptions returns a Meow hash configured by the given options. Later
+// options override earlier ones. It panics if the options are invalid.
+func NewWithOptions(opts ...Option) *Digest {
+	o := options{size: Size}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return NewWithSize(o.seed, o.size)
+}
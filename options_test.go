@@ -0,0 +1,39 @@
+package meow
+
+import "testing"
+
+func TestNewWithOptions(t *testing.T) {
+	b := []byte("options compose")
+	cases := []struct {
+		Name   string
+		Opts   []Option
+		Expect *Digest
+	}{
+		{"Default", nil, New(0)},
+		{"WithSeed", []Option{WithSeed(42)}, New(42)},
+		{"WithSize", []Option{WithSize(8)}, New64(0)},
+		{"WithSeedAndSize", []Option{WithSeed(42), WithSize(4)}, New32(42)},
+		{"WithSizeAndSeed", []Option{WithSize(4), WithSeed(42)}, New32(42)},
+		{"LastWins", []Option{WithSeed(1), WithSize(6), WithSeed(42), WithSize(12)}, NewWithSize(42, 12)},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			h := NewWithOptions(c.Opts...)
+			if *h != *c.Expect {
+				t.Fatalf("got=%+v expect=%+v", *h, *c.Expect)
+			}
+			h.Write(b)
+			c.Expect.Write(b)
+			AssertBytesEqual(t, c.Expect.Sum(nil), h.Sum(nil))
+		})
+	}
+}
+
+func TestNewWithOptionsInvalidSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	NewWithOptions(WithSize(Size + 1))
+}
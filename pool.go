@@ -0,0 +1,24 @@
+package meow
+
+import "sync"
+
+// digests is a pool of zeroized digests.
+var digests = sync.Pool{
+	New: func() interface{} { return &Digest{} },
+}
+
+// Get returns a 128-bit Meow hash with the given seed from a pool. The digest
+// should be returned to the pool with Put once it is no longer needed.
+func Get(seed uint64) *Digest {
+	d := digests.Get().(*Digest)
+	d.seed = seed
+	d.size = Size
+	return d
+}
+
+// Put wipes d and returns it to the pool used by Get. d must not be used after
+// calling Put.
+func Put(d *Digest) {
+	d.Zeroize()
+	digests.Put(d)
+}
@@ -0,0 +1,34 @@
+package meow
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(seed uint64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(seed)))
+			for i := 0; i < 100; i++ {
+				data := make([]byte, r.Intn(1000))
+				r.Read(data)
+
+				d := Get(seed)
+				if d.Size() != Size || d.BytesWritten() != 0 {
+					t.Errorf("Get returned a dirty digest: size=%d length=%d", d.Size(), d.BytesWritten())
+				}
+				d.Write(data)
+				expect := Checksum(seed, data)
+				if got := d.Sum(nil); string(got) != string(expect[:]) {
+					t.Errorf("got=%x expect=%x", got, expect)
+				}
+				Put(d)
+			}
+		}(uint64(g))
+	}
+	wg.Wait()
+}
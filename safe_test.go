@@ -0,0 +1,49 @@
+package meow
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestSafeDigest(t *testing.T) {
+	// Every writer writes the same chunk, so the final state does not depend on
+	// the order in which goroutines acquire the lock, only on each Write being
+	// applied whole.
+	chunk := make([]byte, 100)
+	for i := range chunk {
+		chunk[i] = byte(i)
+	}
+	const writers, writes = 8, 1000
+
+	s := NewSafe(42)
+	var wg sync.WaitGroup
+	for g := 0; g < writers; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				if g%2 == 0 {
+					s.Write(chunk)
+				} else {
+					s.WriteString(string(chunk))
+				}
+				if i%100 == 0 {
+					s.Sum(nil)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	expect := Checksum(42, bytes.Repeat(chunk, writers*writes))
+	if got := s.Sum(nil); !bytes.Equal(got, expect[:]) {
+		t.Fatalf("got=%x expect=%x", got, expect)
+	}
+
+	s.Reset()
+	expect = Checksum(42, nil)
+	if got := s.Sum(nil); !bytes.Equal(got, expect[:]) {
+		t.Fatalf("after Reset got=%x expect=%x", got, expect)
+	}
+}
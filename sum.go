@@ -0,0 +1,118 @@
+package meow
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Sum is a 128-bit Meow checksum. It encodes as lowercase hex in text formats
+// such as JSON, and as a 16-byte blob in SQL databases.
+type Sum [Size]byte
+
+// SumOf returns the Meow checksum of data.
+func SumOf(seed uint64, data []byte) Sum {
+	return checksumOneShot(seed, data)
+}
+
+// String returns the lowercase hex encoding of the checksum.
+func (s Sum) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// Format implements fmt.Formatter. The %x, %s and %v verbs print lowercase
+// hex, and %X prints uppercase hex. Flags, width and precision are applied as
+// they are for a byte slice printed with %x.
+func (s Sum) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'x', 'X':
+	case 's', 'v':
+		verb = 'x'
+	default:
+		fmt.Fprintf(f, "%%!%c(meow.Sum=%s)", verb, s.String())
+		return
+	}
+
+	format := []byte{'%'}
+	for _, flag := range "-+# 0" {
+		if f.Flag(int(flag)) {
+			format = append(format, byte(flag))
+		}
+	}
+	if w, ok := f.Width(); ok {
+		format = strconv.AppendInt(format, int64(w), 10)
+	}
+	if p, ok := f.Precision(); ok {
+		format = append(format, '.')
+		format = strconv.AppendInt(format, int64(p), 10)
+	}
+	format = append(format, byte(verb))
+	fmt.Fprintf(f, string(format), s[:])
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the checksum as
+// lowercase hex.
+func (s Sum) MarshalText() ([]byte, error) {
+	b := make([]byte, hex.EncodedLen(Size))
+	hex.Encode(b, s[:])
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding a hex encoded
+// checksum.
+func (s *Sum) UnmarshalText(text []byte) error {
+	if len(text) != hex.EncodedLen(Size) {
+		return errors.New("meow: invalid checksum length")
+	}
+	var r Sum
+	if _, err := hex.Decode(r[:], text); err != nil {
+		return err
+	}
+	*s = r
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the checksum as a quoted
+// lowercase hex string.
+func (s Sum) MarshalJSON() ([]byte, error) {
+	b := make([]byte, hex.EncodedLen(Size)+2)
+	b[0] = '"'
+	hex.Encode(b[1:], s[:])
+	b[len(b)-1] = '"'
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding a quoted hex string. A
+// JSON null leaves the checksum unchanged.
+func (s *Sum) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return errors.New("meow: checksum must be a JSON string")
+	}
+	return s.UnmarshalText(data[1 : len(data)-1])
+}
+
+// Value implements driver.Valuer, storing the checksum as a 16-byte blob.
+func (s Sum) Value() (driver.Value, error) {
+	return s[:], nil
+}
+
+// Scan implements sql.Scanner. It accepts a 16-byte blob, or a hex string.
+func (s *Sum) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		if len(v) == Size {
+			copy(s[:], v)
+			return nil
+		}
+		return s.UnmarshalText(v)
+	case string:
+		return s.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("meow: cannot scan %T into Sum", src)
+	}
+}
@@ -0,0 +1,182 @@
+package meow
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestDigestString(t *testing.T) {
+	h := New64(0)
+	h.Write([]byte("Hello, World!"))
+	if s := fmt.Sprintf("%s", h); s != "a8cfb4aad7eada8e" {
+		t.Fatalf("got=%s", s)
+	}
+	text, err := h.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != h.String() {
+		t.Fatalf("MarshalText()=%s String()=%s", text, h.String())
+	}
+
+	// Confirm the state is unchanged.
+	h.Write([]byte("!"))
+	if s, expect := h.String(), fmt.Sprintf("%x", Checksum(0, []byte("Hello, World!!"))); s != expect[:16] {
+		t.Fatalf("got=%s expect=%s", s, expect[:16])
+	}
+}
+
+func TestSumText(t *testing.T) {
+	sum := Sum(Checksum(0, []byte("Hello, World!")))
+	text, err := sum.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(text) != "a8cfb4aad7eada8ef007aafe27135386" {
+		t.Fatalf("got=%s", text)
+	}
+
+	var got Sum
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if got != sum {
+		t.Fatalf("got=%s expect=%s", got, sum)
+	}
+
+	for _, bad := range []string{"", "a8cf", "a8cfb4aad7eada8ef007aafe2713538", "zzcfb4aad7eada8ef007aafe27135386", "00000000000000000000000000000zz0"} {
+		if err := got.UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("expected error decoding %q", bad)
+		}
+		if got != sum {
+			t.Errorf("failed decode of %q modified the sum: got=%s expect=%s", bad, got, sum)
+		}
+	}
+}
+
+func TestSumFormat(t *testing.T) {
+	sum := SumOf(0, []byte("Hello, World!"))
+	cases := []struct {
+		Format string
+		Expect string
+	}{
+		{"%x", "a8cfb4aad7eada8ef007aafe27135386"},
+		{"%X", "A8CFB4AAD7EADA8EF007AAFE27135386"},
+		{"%s", "a8cfb4aad7eada8ef007aafe27135386"},
+		{"%v", "a8cfb4aad7eada8ef007aafe27135386"},
+		{"%020x", "a8cfb4aad7eada8ef007aafe27135386"},
+		{"%040x", "00000000a8cfb4aad7eada8ef007aafe27135386"},
+		{"%36X", "    A8CFB4AAD7EADA8EF007AAFE27135386"},
+		{"%-36s|", "a8cfb4aad7eada8ef007aafe27135386    |"},
+		{"%#x", "0xa8cfb4aad7eada8ef007aafe27135386"},
+		{"%.4x", "a8cfb4aa"},
+		{"%d", "%!d(meow.Sum=a8cfb4aad7eada8ef007aafe27135386)"},
+	}
+	for _, c := range cases {
+		if got := fmt.Sprintf(c.Format, sum); got != c.Expect {
+			t.Errorf("Sprintf(%q) got=%s expect=%s", c.Format, got, c.Expect)
+		}
+	}
+}
+
+func TestSumOf(t *testing.T) {
+	data := []byte("Hello, World!")
+	if got, expect := SumOf(42, data), Checksum(42, data); got != Sum(expect) {
+		t.Fatalf("got=%s expect=%x", got, expect)
+	}
+}
+
+func TestSumJSON(t *testing.T) {
+	type record struct {
+		Checksum Sum `json:"checksum"`
+	}
+	r := record{Checksum: SumOf(0, []byte("Hello, World!"))}
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"checksum":"a8cfb4aad7eada8ef007aafe27135386"}` {
+		t.Fatalf("got=%s", b)
+	}
+
+	var got record
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != r {
+		t.Fatalf("got=%s expect=%s", got.Checksum, r.Checksum)
+	}
+}
+
+func TestSumMarshalJSON(t *testing.T) {
+	sum := SumOf(0, []byte("Hello, World!"))
+	b, err := sum.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"a8cfb4aad7eada8ef007aafe27135386"` {
+		t.Fatalf("got=%s", b)
+	}
+
+	var got Sum
+	if err := got.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if got != sum {
+		t.Fatalf("got=%s expect=%s", got, sum)
+	}
+	if err := got.UnmarshalJSON([]byte("null")); err != nil || got != sum {
+		t.Fatalf("null: err=%v got=%s", err, got)
+	}
+
+	for _, bad := range []string{
+		``,
+		`"`,
+		`""`,
+		`"a8cf"`,
+		`"a8cfb4aad7eada8ef007aafe2713538"`,
+		`"a8cfb4aad7eada8ef007aafe2713538600"`,
+		`"zzcfb4aad7eada8ef007aafe27135386"`,
+		`a8cfb4aad7eada8ef007aafe27135386`,
+		`123`,
+	} {
+		if err := got.UnmarshalJSON([]byte(bad)); err == nil {
+			t.Errorf("expected error decoding %s", bad)
+		}
+		if err := json.Unmarshal([]byte(`{"checksum":`+bad+`}`), &struct {
+			Checksum Sum `json:"checksum"`
+		}{}); err == nil {
+			t.Errorf("expected error decoding field %s", bad)
+		}
+	}
+}
+
+func TestSumSQL(t *testing.T) {
+	sum := SumOf(0, []byte("Hello, World!"))
+	v, err := sum.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !driver.IsValue(v) {
+		t.Fatalf("%T is not a driver value", v)
+	}
+
+	for _, src := range []interface{}{v, sum.String(), []byte(sum.String())} {
+		var got Sum
+		if err := got.Scan(src); err != nil {
+			t.Fatalf("Scan(%T): %v", src, err)
+		}
+		if got != sum {
+			t.Fatalf("Scan(%T) got=%s expect=%s", src, got, sum)
+		}
+	}
+
+	for _, src := range []interface{}{nil, int64(1), []byte{1, 2, 3}, "a8cf"} {
+		var got Sum
+		if err := got.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) expected error", src)
+		}
+	}
+}
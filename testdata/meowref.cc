@@ -0,0 +1,46 @@
+#include <stdio.h>
+#include <inttypes.h>
+#include <stdlib.h>
+#include <assert.h>
+#include <immintrin.h>
+#include <string.h>
+
+#include "meow_hash.h"
+
+// Read a little-endian uint64 from the buffer.
+uint64_t read_uint64(const uint8_t *b)
+{
+    uint64_t x = 0;
+    for (int i = 7; i >= 0; i--)
+    {
+        x = (x << 8) | b[i];
+    }
+    return x;
+}
+
+// Hash requests read from stdin, writing each hash to stdout. A request is
+// the seed and input length as little-endian uint64s, followed by the input.
+// The response is the 16-byte hash.
+int main(int argc, char **argv)
+{
+    uint8_t header[16];
+    while (fread(header, 1, sizeof(header), stdin) == sizeof(header))
+    {
+        uint64_t seed = read_uint64(header);
+        size_t len = (size_t)read_uint64(header + 8);
+
+        uint8_t *input = (uint8_t *)malloc(len + 1);
+        assert(input);
+        if (fread(input, 1, len, stdin) != len)
+        {
+            fprintf(stderr, "meowref: short input\n");
+            return 1;
+        }
+
+        meow_hash hash = MeowHash1(seed, len, input);
+        fwrite(&hash.u64[0], 1, 16, stdout);
+        fflush(stdout);
+        free(input);
+    }
+    return 0;
+}
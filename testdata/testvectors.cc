@@ -126,8 +126,10 @@ size_t *generate_lengths(size_t *n)
 {
     size_t num_small = 32;
     size_t num_modulo = 256;
+    size_t edge[] = {255, 256, 257, 511, 512, 513, 4095, 4096, 4097};
+    size_t num_edge = sizeof(edge) / sizeof(edge[0]);
 
-    *n = num_small + num_modulo;
+    *n = num_small + num_modulo + num_edge;
     size_t *lengths = (size_t *)malloc(*n * sizeof(size_t));
     assert(lengths);
 
@@ -140,6 +142,9 @@ size_t *generate_lengths(size_t *n)
     // Modulo lengths provides larger hash inputs of all possible values modulo 256.
     modulo_lengths(lengths + num_small, 251, 8 << 10, num_modulo);
 
+    // Lengths either side of block boundaries.
+    memcpy(lengths + num_small + num_modulo, edge, sizeof(edge));
+
     return lengths;
 }
 
@@ -1,6 +1,9 @@
 package meow
 
-import "math/rand"
+import (
+	"crypto/aes"
+	"math/rand"
+)
 
 // checksumFunc is a method of computing a Meow checksum.
 type checksumFunc func(uint64, []byte) []byte
@@ -56,7 +59,37 @@ func checksumHashWithIntermediateSum(seed uint64, data []byte) []byte {
 
 // checksumPureGo computes the checksum with the fallback Go implementation.
 func checksumPureGo(seed uint64, data []byte) []byte {
-	cksum := make([]byte, Size)
-	checksumgo(seed, cksum, data)
-	return cksum
+	cksum := checksumgo(seed, data)
+	return cksum[:]
+}
+
+// checksumHashWithMarshal computes the checksum and serializes the hash state
+// inbetween, resuming in a fresh Digest. Intended to confirm that the binary
+// encoding captures the entire state.
+func checksumHashWithMarshal(seed uint64, data []byte) []byte {
+	h := New(seed)
+	half := len(data) / 2
+	h.Write(data[:half])
+	state, err := h.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	var r Digest
+	if err := r.UnmarshalBinary(state); err != nil {
+		panic(err)
+	}
+	r.Write(data[half:])
+	return r.Sum(nil)
+}
+
+// blocksReference is the original straightforward implementation of blocksgo.
+// Intended to confirm optimized versions produce identical streams.
+func blocksReference(s, src []byte) {
+	for len(src) >= BlockSize {
+		for i := 0; i < BlockSize; i += aes.BlockSize {
+			aesdec(src[i:], s[i:], s[i:])
+		}
+		src = src[BlockSize:]
+	}
 }
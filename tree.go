@@ -0,0 +1,80 @@
+package meow
+
+import (
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ChecksumTree returns the Meow checksums of every regular file under root,
+// keyed by their path as walked by filepath.WalkDir, so that each key may be
+// passed to ChecksumFile. Symbolic links and other special files are skipped,
+// and symbolic links to directories are not followed. Files are hashed by a
+// pool of workers goroutines, or GOMAXPROCS if workers is not positive. The
+// walk stops at the first error, which is returned as an *Error.
+func ChecksumTree(seed uint64, root string, workers int) (map[string][Size]byte, error) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		mu       sync.Mutex
+		sums     = map[string][Size]byte{}
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+	}
+	failed := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr
+	}
+
+	paths := make(chan string)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				sum, err := ChecksumFile(seed, path)
+				if err != nil {
+					fail(err)
+					continue
+				}
+				mu.Lock()
+				sums[path] = sum
+				mu.Unlock()
+			}
+		}()
+	}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fileError("walk", path, err)
+		}
+		if err := failed(); err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			paths <- path
+		}
+		return nil
+	})
+	close(paths)
+	wg.Wait()
+
+	if err != nil {
+		fail(err)
+	}
+	if err := failed(); err != nil {
+		return nil, err
+	}
+	return sums, nil
+}
@@ -0,0 +1,63 @@
+package meow
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksumTree(t *testing.T) {
+	root := t.TempDir()
+	files := []string{"a", "b/c", "b/d/e", "b/d/f", "g/h"}
+	for _, name := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data := make([]byte, rand.Intn(3*readSize))
+		rand.Read(data)
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "b"), filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	for _, workers := range []int{0, 1, 3} {
+		sums, err := ChecksumTree(7, root, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sums) != len(files) {
+			t.Errorf("workers=%d: got %d checksums expect %d", workers, len(sums), len(files))
+		}
+		for _, name := range files {
+			path := filepath.Join(root, filepath.FromSlash(name))
+			expect, err := ChecksumFile(7, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, ok := sums[path]; !ok || got != expect {
+				t.Errorf("workers=%d %s: got=%x expect=%x", workers, name, got, expect)
+			}
+		}
+	}
+}
+
+func TestChecksumTreeNotExist(t *testing.T) {
+	_, err := ChecksumTree(0, filepath.Join(t.TempDir(), "missing"), 2)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got error %v expect not exist", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Op != "walk" {
+		t.Fatalf("got error %#v expect *Error with Op walk", err)
+	}
+}
@@ -0,0 +1,12 @@
+package meow
+
+import "unsafe"
+
+// stringBytes returns a byte slice sharing the memory of s. The result must
+// not be modified or retained.
+func stringBytes(s string) []byte {
+	return *(*[]byte)(unsafe.Pointer(&struct {
+		string
+		int
+	}{s, len(s)}))
+}
@@ -0,0 +1,77 @@
+package meow
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// Equal reports whether a and b are the same checksum.
+func Equal(a, b [Size]byte) bool {
+	return a == b
+}
+
+// EqualConstantTime reports whether a and b are the same checksum, in time
+// independent of their contents. Note Meow is not a cryptographic hash, and is
+// not a substitute for a MAC.
+func EqualConstantTime(a, b [Size]byte) bool {
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// Verify reports whether expected is the Meow checksum of data.
+func Verify(seed uint64, data []byte, expected [Size]byte) bool {
+	return Equal(Checksum(seed, data), expected)
+}
+
+// VerifyReader reports whether expected is the Meow checksum of all data read
+// from r until EOF. It returns any error other than io.EOF encountered while
+// reading.
+func VerifyReader(seed uint64, r io.Reader, expected [Size]byte) (bool, error) {
+	sum, err := ChecksumReader(seed, r)
+	if err != nil {
+		return false, err
+	}
+	return Equal(sum, expected), nil
+}
+
+// VerifyHex reports whether expectedHex is the hex encoded Meow checksum of
+// data. expectedHex may be truncated to any whole number of bytes, as produced
+// by a Digest from NewWithSize, in which case only that prefix of the checksum
+// is compared. An error is returned if expectedHex is not valid hex of 1 to 16
+// bytes.
+func VerifyHex(seed uint64, data []byte, expectedHex string) (bool, error) {
+	expected, err := decodeHexChecksum(expectedHex)
+	if err != nil {
+		return false, err
+	}
+	sum := Checksum(seed, data)
+	return bytes.Equal(sum[:len(expected)], expected), nil
+}
+
+// VerifyHexReader is like VerifyHex, but hashes all data read from r until
+// EOF. expectedHex is checked before anything is read.
+func VerifyHexReader(seed uint64, r io.Reader, expectedHex string) (bool, error) {
+	expected, err := decodeHexChecksum(expectedHex)
+	if err != nil {
+		return false, err
+	}
+	sum, err := ChecksumReader(seed, r)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(sum[:len(expected)], expected), nil
+}
+
+// decodeHexChecksum decodes a hex encoded, possibly truncated, checksum.
+func decodeHexChecksum(s string) ([]byte, error) {
+	if len(s) == 0 || len(s) > hex.EncodedLen(Size) {
+		return nil, errors.New("meow: invalid checksum length")
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
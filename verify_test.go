@@ -0,0 +1,119 @@
+package meow
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestEqual(t *testing.T) {
+	a := Checksum(0, []byte("Hello, World!"))
+	first, last := a, a
+	first[0] ^= 1
+	last[Size-1] ^= 0x80
+
+	cases := []struct {
+		Name   string
+		B      [Size]byte
+		Expect bool
+	}{
+		{"Equal", a, true},
+		{"DifferentFirstByte", first, false},
+		{"DifferentLastByte", last, false},
+	}
+	for _, c := range cases {
+		if got := Equal(a, c.B); got != c.Expect {
+			t.Errorf("%s: Equal()=%v expect=%v", c.Name, got, c.Expect)
+		}
+		if got := EqualConstantTime(a, c.B); got != c.Expect {
+			t.Errorf("%s: EqualConstantTime()=%v expect=%v", c.Name, got, c.Expect)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := []byte("integrity checks are a one-liner")
+	sum := Checksum(3, data)
+	if !Verify(3, data, sum) {
+		t.Error("expected match")
+	}
+	if Verify(4, data, sum) {
+		t.Error("expected mismatch for different seed")
+	}
+	if Verify(3, data[1:], sum) {
+		t.Error("expected mismatch for different data")
+	}
+}
+
+func TestVerifyReader(t *testing.T) {
+	data := bytes.Repeat([]byte("downloaded file "), 1000)
+	sum := Checksum(3, data)
+
+	ok, err := VerifyReader(3, bytes.NewReader(data), sum)
+	if err != nil || !ok {
+		t.Errorf("VerifyReader()=%v, %v expect match", ok, err)
+	}
+	ok, err = VerifyReader(3, bytes.NewReader(data[1:]), sum)
+	if err != nil || ok {
+		t.Errorf("VerifyReader()=%v, %v expect mismatch", ok, err)
+	}
+
+	errRead := errors.New("connection reset")
+	r := io.MultiReader(bytes.NewReader(data[:100]), iotest.ErrReader(errRead))
+	if _, err := VerifyReader(3, r, sum); !errors.Is(err, errRead) {
+		t.Errorf("got error %v expect %v", err, errRead)
+	}
+}
+
+func TestVerifyHex(t *testing.T) {
+	data := []byte("Hello, World!")
+	cases := []struct {
+		Hex    string
+		Expect bool
+	}{
+		{"a8cfb4aad7eada8ef007aafe27135386", true},
+		{"A8CFB4AAD7EADA8EF007AAFE27135386", true},
+		{"a8cfb4aad7eada8e", true},
+		{"a8cfb4aa", true},
+		{"a8", true},
+		{"a8cfb4aad7eada8ef007aafe27135387", false},
+		{"a8cfb4ab", false},
+	}
+	for _, c := range cases {
+		ok, err := VerifyHex(0, data, c.Hex)
+		if err != nil {
+			t.Fatalf("VerifyHex(%q): %v", c.Hex, err)
+		}
+		if ok != c.Expect {
+			t.Errorf("VerifyHex(%q)=%v expect=%v", c.Hex, ok, c.Expect)
+		}
+		ok, err = VerifyHexReader(0, iotest.HalfReader(bytes.NewReader(data)), c.Hex)
+		if err != nil {
+			t.Fatalf("VerifyHexReader(%q): %v", c.Hex, err)
+		}
+		if ok != c.Expect {
+			t.Errorf("VerifyHexReader(%q)=%v expect=%v", c.Hex, ok, c.Expect)
+		}
+	}
+
+	// A truncated digest verifies against its own hex.
+	h := NewWithSize(0, 6)
+	h.Write(data)
+	if ok, err := VerifyHex(0, data, h.String()); !ok || err != nil {
+		t.Errorf("truncated digest %s: ok=%v err=%v", h, ok, err)
+	}
+}
+
+func TestVerifyHexMalformed(t *testing.T) {
+	for _, s := range []string{"", "a", "a8c", "zz", "a8cfb4aad7eada8ef007aafe2713538600"} {
+		if _, err := VerifyHex(0, nil, s); err == nil {
+			t.Errorf("VerifyHex(%q) expected error", s)
+		}
+		r := iotest.ErrReader(errors.New("must not be read"))
+		if _, err := VerifyHexReader(0, r, s); err == nil || err.Error() == "must not be read" {
+			t.Errorf("VerifyHexReader(%q) got error %v", s, err)
+		}
+	}
+}